@@ -0,0 +1,193 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Defaults for the adaptive run bounds when they are not set explicitly
+const (
+	DefaultMinRuns = 3
+	DefaultMaxRuns = 100
+)
+
+// CIStatistic selects which estimator the adaptive mode targets
+type CIStatistic string
+
+const (
+	CIStatisticMean   CIStatistic = "mean"
+	CIStatisticMedian CIStatistic = "median"
+)
+
+// StopReason records why the measurement loop ended
+type StopReason string
+
+const (
+	StopReasonRunCount    StopReason = "run-count"   // Fixed number of runs completed
+	StopReasonTargetCI    StopReason = "target-ci"   // Relative confidence interval reached the target
+	StopReasonMaxRuns     StopReason = "max-runs"    // Adaptive mode hit the run limit first
+	StopReasonMaxTime     StopReason = "max-time"    // Adaptive mode hit the time budget first
+	StopReasonInterrupted StopReason = "interrupted" // The benchmark context was cancelled (e.g. Ctrl-C)
+)
+
+// Description returns a human-readable explanation of the stop reason
+func (r StopReason) Description() string {
+	switch r {
+	case StopReasonRunCount:
+		return "completed the requested number of runs"
+	case StopReasonTargetCI:
+		return "confidence interval reached the target"
+	case StopReasonMaxRuns:
+		return "reached the maximum number of runs"
+	case StopReasonMaxTime:
+		return "reached the time budget"
+	case StopReasonInterrupted:
+		return "interrupted"
+	default:
+		return string(r)
+	}
+}
+
+// Adaptive reports whether the run count is driven by a target confidence interval
+func (c Config) Adaptive() bool {
+	return c.TargetCI > 0
+}
+
+// withAdaptiveDefaults fills in unset adaptive bounds so a bare TargetCI is usable
+func (c Config) withAdaptiveDefaults() Config {
+	if !c.Adaptive() {
+		return c
+	}
+	if c.MinRuns <= 0 {
+		c.MinRuns = DefaultMinRuns
+	}
+	if c.MaxRuns <= 0 {
+		c.MaxRuns = DefaultMaxRuns
+	}
+	if c.CIStatistic == "" {
+		c.CIStatistic = CIStatisticMean
+	}
+	return c
+}
+
+// ParseCIStatistic validates a --ci-statistic value
+func ParseCIStatistic(s string) (CIStatistic, error) {
+	switch CIStatistic(s) {
+	case CIStatisticMean, CIStatisticMedian:
+		return CIStatistic(s), nil
+	default:
+		return "", fmt.Errorf("invalid CI statistic %q (must be mean or median)", s)
+	}
+}
+
+// MeanConfidenceInterval returns the two-sided confidence interval of the mean
+// using Student's t-distribution
+func MeanConfidenceInterval(durations []float64, level float64) (low, high float64) {
+	n := len(durations)
+	if n == 0 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, d := range durations {
+		mean += d
+	}
+	mean /= float64(n)
+
+	if n < 2 {
+		return mean, mean
+	}
+
+	// Sample standard deviation (n-1), as the interval estimates a population parameter
+	sumSquares := 0.0
+	for _, d := range durations {
+		sumSquares += (d - mean) * (d - mean)
+	}
+	stdErr := math.Sqrt(sumSquares/float64(n-1)) / math.Sqrt(float64(n))
+
+	t := studentTQuantile(1-(1-level)/2, float64(n-1))
+	return mean - t*stdErr, mean + t*stdErr
+}
+
+// RelativeCI returns the half-width of the confidence interval of the chosen
+// statistic divided by the statistic itself (0.02 = ±2%). The intervals are
+// the reported ones: the t-interval of the mean and the bootstrap interval of
+// the median.
+func RelativeCI(durations []float64, statistic CIStatistic, level float64) float64 {
+	if len(durations) < 2 {
+		return math.Inf(1)
+	}
+
+	var low, high, center float64
+	if statistic == CIStatisticMedian {
+		low, high = BootstrapConfidenceInterval(durations, CIStatisticMedian, level)
+		center = CalculateStatistics(durations).Median
+	} else {
+		low, high = MeanConfidenceInterval(durations, level)
+		center = (low + high) / 2
+	}
+
+	if center <= 0 {
+		return math.Inf(1)
+	}
+	return (high - low) / 2 / center
+}
+
+// stopReason decides whether the measurement loop should end before starting
+// another run. overhead is the calibrated shell overhead.
+func stopReason(config Config, runs []RunResult, overhead time.Duration, measureStart time.Time) (StopReason, bool) {
+	if !config.Adaptive() {
+		if len(runs) >= config.Runs {
+			return StopReasonRunCount, true
+		}
+		return "", false
+	}
+
+	if len(runs) >= config.MinRuns {
+		if RelativeCI(adaptiveDurations(config, runs, overhead), config.CIStatistic, config.confidence()) <= config.TargetCI {
+			return StopReasonTargetCI, true
+		}
+	}
+	if len(runs) >= config.MaxRuns {
+		return StopReasonMaxRuns, true
+	}
+	if config.MaxTime > 0 && time.Since(measureStart) >= config.MaxTime {
+		return StopReasonMaxTime, true
+	}
+
+	return "", false
+}
+
+// successfulDurations returns the durations in seconds of the successful runs
+func successfulDurations(runs []RunResult) []float64 {
+	durations := make([]float64, 0, len(runs))
+	for _, run := range runs {
+		if run.Success {
+			durations = append(durations, run.Duration.Seconds())
+		}
+	}
+	return durations
+}
+
+// adaptiveDurations returns the durations the statistics will be computed
+// from once the measurement loop ends: the successful runs minus the shell
+// overhead, without the outliers when they are trimmed. The target CI is
+// checked against them so that the reported interval is the one that reached it.
+func adaptiveDurations(config Config, runs []RunResult, overhead time.Duration) []float64 {
+	durations := subtractOverhead(successfulDurations(runs), overhead)
+	if !config.TrimOutliers {
+		return durations
+	}
+	lower, upper, ok := OutlierFences(durations, config.outlierMethod())
+	if !ok {
+		return durations
+	}
+	kept := make([]float64, 0, len(durations))
+	for _, d := range durations {
+		if d >= lower && d <= upper {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
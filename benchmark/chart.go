@@ -0,0 +1,58 @@
+package benchmark
+
+import (
+	"fmt"
+	"strings"
+)
+
+// chartWidth is the width of the bar area in characters
+const chartWidth = 50
+
+// bar is a single row of an ASCII bar chart
+type bar struct {
+	Label string
+	Value float64
+}
+
+// barChartString renders a horizontal ASCII bar chart scaled to the largest value
+func barChartString(title string, bars []bar) string {
+	if len(bars) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s\n", title))
+	sb.WriteString(fmt.Sprintf("%s\n\n", strings.Repeat("=", len(title))))
+
+	// Find max value for scaling and widest label for alignment
+	maxValue := 0.0
+	labelWidth := 0
+	for _, b := range bars {
+		if b.Value > maxValue {
+			maxValue = b.Value
+		}
+		if len(b.Label) > labelWidth {
+			labelWidth = len(b.Label)
+		}
+	}
+
+	for _, b := range bars {
+		sb.WriteString(fmt.Sprintf("%*s │%s %s\n",
+			labelWidth, b.Label, Bar(b.Value, maxValue, chartWidth), formatShortDuration(b.Value)))
+	}
+
+	sb.WriteString(fmt.Sprintf("%s └%s\n", strings.Repeat(" ", labelWidth), strings.Repeat("─", chartWidth+10)))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// Bar draws value as a bar of up to width characters, where scale is drawn at
+// full width. Any positive value gets at least one character.
+func Bar(value, scale float64, width int) string {
+	if value <= 0 || scale <= 0 {
+		return ""
+	}
+	return strings.Repeat("█", max(int((value/scale)*float64(width)), 1))
+}
@@ -0,0 +1,291 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Session describes one invocation of a benchmark. A resumed benchmark has
+// one session per invocation, and each run records the session it came from.
+type Session struct {
+	Number    int
+	StartTime time.Time
+	Runs      int // Measured runs completed in this session
+}
+
+// checkpointRecord is one line of a checkpoint file: the start of a session,
+// a completed measured run, or the end of a session that was not interrupted
+type checkpointRecord struct {
+	Session      *checkpointSession `json:"session,omitempty"`
+	Run          *RunResult         `json:"run,omitempty"`
+	HookFailures []HookFailure      `json:"hookFailures,omitempty"`
+	Finished     *time.Time         `json:"finished,omitempty"`
+}
+
+// checkpointSession is written when a session starts
+type checkpointSession struct {
+	Number      int               `json:"number"`
+	StartTime   time.Time         `json:"startTime"`
+	Fingerprint map[string]string `json:"fingerprint"`
+}
+
+// checkpoint appends completed runs to the checkpoint file. The file is only
+// opened, and the session header written, with the first record, so that a
+// benchmark that fails before its first run (e.g. in setup) leaves an earlier
+// checkpoint as it was.
+type checkpoint struct {
+	path    string
+	flags   int
+	session checkpointSession
+	file    *os.File
+}
+
+// CheckpointPath returns the checkpoint file of the benchmark
+func (c Config) CheckpointPath() string {
+	return filepath.Join(c.OutputDir, c.Name+".checkpoint.jsonl")
+}
+
+// fingerprint returns the settings that must not change between sessions of
+// a resumed benchmark. Environment values are hashed so secrets stay out of the file.
+func (c Config) fingerprint() map[string]string {
+	env := sha256.Sum256([]byte(strings.Join(c.Env, "\x00")))
+	return map[string]string{
+		"command":           c.Command,
+		"shell":             c.ShellName(),
+		"parameter":         c.Parameter.Name + "=" + c.Parameter.Value,
+		"cwd":               c.Dir,
+		"env":               hex.EncodeToString(env[:]),
+		"cleanEnv":          fmt.Sprintf("%t", c.CleanEnv),
+		"timeout":           c.Timeout.String(),
+		"setup":             c.Setup,
+		"prepare":           c.Prepare,
+		"conclude":          c.Conclude,
+		"cleanup":           c.Cleanup,
+		"expectedExitCodes": fmt.Sprintf("%v", c.ExpectedExitCodes),
+		"ignoreFailure":     fmt.Sprintf("%t", c.IgnoreFailure),
+		"service":           c.Service,
+		"readiness":         c.Readiness.Description(),
+		"measureStartup":    fmt.Sprintf("%t", c.MeasureStartup),
+	}
+}
+
+// openCheckpoint prepares a new session of the checkpoint file. With Resume
+// set, the runs and sessions of the existing file are loaded into result
+// first, and a benchmark without a checkpoint starts afresh. Otherwise an
+// existing checkpoint is replaced once the first run completes, unless it
+// holds the runs of an interrupted benchmark.
+func openCheckpoint(config Config, result *Result) (*checkpoint, error) {
+	path := config.CheckpointPath()
+	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if config.Resume {
+		validSize, err := loadCheckpoint(path, config, result)
+		if err != nil {
+			return nil, err
+		}
+		// Drop a partially written last line left by a crash
+		if validSize > 0 {
+			if err := os.Truncate(path, validSize); err != nil {
+				return nil, fmt.Errorf("failed to repair checkpoint: %w", err)
+			}
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	} else if finished, err := checkpointFinished(path); err != nil {
+		return nil, err
+	} else if !finished {
+		return nil, fmt.Errorf("%s holds an interrupted benchmark: continue it with --resume, or delete it (or use --no-checkpoint) to start over", path)
+	}
+
+	session := result.Sessions[len(result.Sessions)-1]
+	return &checkpoint{
+		path:  path,
+		flags: flags,
+		session: checkpointSession{
+			Number:      session.Number,
+			StartTime:   session.StartTime,
+			Fingerprint: config.fingerprint(),
+		},
+	}, nil
+}
+
+// loadCheckpoint reads the sessions and runs of a checkpoint file into result,
+// adds the new session, and returns the size of the file up to its last
+// complete line. A missing file leaves result as is: a benchmark that never
+// started has no checkpoint, e.g. the last ones of an interrupted scan.
+func loadCheckpoint(path string, config Config, result *Result) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var sessions []Session
+	var runs []RunResult
+	var hookFailures []HookFailure
+	var validSize int64
+
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// A last line without newline was cut short and is ignored
+			break
+		}
+
+		var record checkpointRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return 0, fmt.Errorf("%s:%d: invalid checkpoint record: %w", path, lineNumber, err)
+		}
+
+		switch {
+		case record.Session != nil:
+			if mismatch := fingerprintMismatch(record.Session.Fingerprint, config.fingerprint()); len(mismatch) > 0 {
+				return 0, fmt.Errorf("cannot resume %s: the checkpoint was recorded with a different %s", path, strings.Join(mismatch, ", "))
+			}
+			sessions = append(sessions, Session{Number: record.Session.Number, StartTime: record.Session.StartTime})
+		case record.Run != nil && len(sessions) > 0:
+			runs = append(runs, *record.Run)
+			hookFailures = append(hookFailures, record.HookFailures...)
+			sessions[len(sessions)-1].Runs++
+		case record.Finished != nil && len(sessions) > 0:
+			// A finished benchmark may be extended by another session
+		default:
+			return 0, fmt.Errorf("%s:%d: unexpected checkpoint record", path, lineNumber)
+		}
+		validSize += int64(len(line))
+	}
+
+	// An empty file holds nothing to resume
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	current := result.Sessions[0]
+	current.Number = sessions[len(sessions)-1].Number + 1
+	result.Sessions = append(sessions, current)
+	result.Runs = append(result.Runs, runs...)
+	result.HookFailures = append(result.HookFailures, hookFailures...)
+
+	return validSize, nil
+}
+
+// fingerprintMismatch returns the sorted names of the settings that differ
+func fingerprintMismatch(recorded, current map[string]string) []string {
+	var mismatch []string
+	for name, value := range current {
+		if recorded[name] != value {
+			mismatch = append(mismatch, name)
+		}
+	}
+	sort.Strings(mismatch)
+	return mismatch
+}
+
+// checkpointFinished reports whether the benchmark recorded at path lost
+// nothing, i.e. whether no run was recorded after its last session finished.
+// A missing or empty checkpoint, or one that holds no runs, counts as
+// finished, as there is nothing to resume.
+func checkpointFinished(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	pending := 0
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var record checkpointRecord
+		if json.Unmarshal(line, &record) != nil {
+			continue // Empty, or cut short by a crash
+		}
+		switch {
+		case record.Run != nil:
+			pending++
+		case record.Finished != nil:
+			pending = 0
+		}
+	}
+	return pending == 0, nil
+}
+
+// finish records that the session ended without being interrupted
+func (cp *checkpoint) finish(end time.Time) error {
+	return cp.write(checkpointRecord{Finished: &end})
+}
+
+// appendRun records a completed measured run
+func (cp *checkpoint) appendRun(run RunResult, hookFailures []HookFailure) error {
+	return cp.write(checkpointRecord{Run: &run, HookFailures: hookFailures})
+}
+
+// write appends a record and syncs it to disk so it survives a crash. The
+// first record opens the file and starts the session.
+func (cp *checkpoint) write(record checkpointRecord) error {
+	if cp.file == nil {
+		file, err := os.OpenFile(cp.path, cp.flags, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open checkpoint: %w", err)
+		}
+		cp.file = file
+		if err := cp.write(checkpointRecord{Session: &cp.session}); err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := cp.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return cp.file.Sync()
+}
+
+// close closes the checkpoint file, if a record was written
+func (cp *checkpoint) close() {
+	if cp.file != nil {
+		cp.file.Close()
+	}
+}
+
+// formatSessions describes which runs came from which session, e.g.
+// "runs 1-13 in session 1 (Mon, 02 Jan 2006 15:04:05 MST), runs 14-20 in session 2 (...)"
+func formatSessions(result *Result) string {
+	var parts []string
+	first := 1
+	for _, session := range result.Sessions {
+		if session.Runs == 0 {
+			continue
+		}
+
+		var runs string
+		switch session.Runs {
+		case 1:
+			runs = fmt.Sprintf("run %d", first)
+		default:
+			runs = fmt.Sprintf("runs %d-%d", first, first+session.Runs-1)
+		}
+		parts = append(parts, fmt.Sprintf("%s in session %d (%s)", runs, session.Number, session.StartTime.Format(time.RFC1123)))
+		first += session.Runs
+	}
+	return strings.Join(parts, ", ")
+}
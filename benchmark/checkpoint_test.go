@@ -0,0 +1,82 @@
+package benchmark
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func checkpointConfig(t *testing.T, dir string) Config {
+	t.Helper()
+	return Config{
+		Command:    "true",
+		Runs:       2,
+		Name:       "cp",
+		OutputDir:  dir,
+		Shell:      "sh",
+		SkipWarmup: true,
+		Checkpoint: true,
+	}
+}
+
+func TestCheckpointAfterFailedSetup(t *testing.T) {
+	dir := t.TempDir()
+	config := checkpointConfig(t, dir)
+
+	// A benchmark that fails before its first run leaves no checkpoint behind
+	failing := config
+	failing.Setup = "exit 1"
+	if _, err := Run(context.Background(), failing); err == nil {
+		t.Fatal("Run with a failing setup succeeded")
+	}
+	if _, err := os.Stat(config.CheckpointPath()); !os.IsNotExist(err) {
+		t.Errorf("checkpoint after a failed setup: %v, want none", err)
+	}
+
+	// So a second run with the same name starts without --resume
+	result, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if len(result.Runs) != 2 {
+		t.Errorf("second run measured %d runs, want 2", len(result.Runs))
+	}
+
+	// A finished checkpoint is replaced, and one failing run later keeps it finished
+	if _, err := Run(context.Background(), failing); err == nil {
+		t.Fatal("Run with a failing setup succeeded")
+	}
+	if _, err := Run(context.Background(), config); err != nil {
+		t.Fatalf("run after a finished checkpoint: %v", err)
+	}
+}
+
+func TestCheckpointFinished(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		content  string
+		finished bool
+	}{
+		{"empty", "", true},
+		{"session only", `{"session":{"number":1}}` + "\n", true},
+		{"cut short", `{"session":{"number":1}}` + "\n" + `{"run":{"RunNum`, true},
+		{"interrupted", `{"session":{"number":1}}` + "\n" + `{"run":{"RunNumber":1}}` + "\n", false},
+		{"finished", `{"session":{"number":1}}` + "\n" + `{"run":{"RunNumber":1}}` + "\n" + `{"finished":"2026-01-02T15:04:05Z"}` + "\n", true},
+		{"resumed and interrupted", `{"session":{"number":1}}` + "\n" + `{"finished":"2026-01-02T15:04:05Z"}` + "\n" + `{"session":{"number":2}}` + "\n" + `{"run":{"RunNumber":1}}` + "\n", false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".jsonl")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		finished, err := checkpointFinished(path)
+		if err != nil || finished != tt.finished {
+			t.Errorf("%s: finished = %t, %v, want %t", tt.name, finished, err, tt.finished)
+		}
+	}
+	if finished, err := checkpointFinished(filepath.Join(dir, "missing.jsonl")); !finished || err != nil {
+		t.Errorf("missing: finished = %t, %v, want true", finished, err)
+	}
+}
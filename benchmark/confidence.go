@@ -0,0 +1,133 @@
+package benchmark
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultConfidence is the confidence level of the reported intervals and of
+// the adaptive run count
+const DefaultConfidence = 0.95
+
+// Bootstrap parameters. The generator is seeded with a constant so a report
+// recomputed from the same runs shows the same intervals.
+const (
+	bootstrapResamples = 10000
+	bootstrapSeed      = 1
+)
+
+// ConfidenceInterval is a two-sided interval estimate of a statistic
+type ConfidenceInterval struct {
+	Low  float64 `json:"low"`  // Lower bound in seconds
+	High float64 `json:"high"` // Upper bound in seconds
+}
+
+// Valid reports whether the interval was computed (it needs two runs or more)
+func (ci ConfidenceInterval) Valid() bool {
+	return ci.High > 0
+}
+
+// String formats the interval as a range, e.g. "1.021s–1.094s"
+func (ci ConfidenceInterval) String() string {
+	return formatShortDuration(ci.Low) + "–" + formatShortDuration(ci.High)
+}
+
+// ParseConfidence parses a --confidence value given as a percentage ("95%")
+// or a fraction ("0.95")
+func ParseConfidence(value string) (float64, error) {
+	return parseProportion("--confidence", value, "95%", "0.95")
+}
+
+// ParseTargetCI parses a --target-ci value given as a percentage ("2%") or a
+// fraction ("0.02"). An empty value means no target.
+func ParseTargetCI(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return parseProportion("--target-ci", value, "2%", "0.02")
+}
+
+// parseProportion parses the value of flag as a percentage or a fraction
+// strictly between 0 and 1. A bare number is always a fraction, so "95" is
+// rejected rather than guessed to mean 95%. percentage and fraction are the
+// examples shown in the error messages.
+func parseProportion(flag, value, percentage, fraction string) (float64, error) {
+	percent := strings.HasSuffix(value, "%")
+	level, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: expected a percentage like '%s' or a fraction like '%s'", flag, value, percentage, fraction)
+	}
+	if percent {
+		level /= 100
+	} else if level >= 1 && level < 100 {
+		return 0, fmt.Errorf("invalid %s %q: a bare number is a fraction, use '%s%%' for a percentage", flag, value, value)
+	}
+	if level <= 0 || level >= 1 {
+		return 0, fmt.Errorf("%s must be between 0%% and 100%%, got %q", flag, value)
+	}
+	return level, nil
+}
+
+// FormatConfidence formats a confidence level as a percentage, e.g. "95%"
+func FormatConfidence(level float64) string {
+	return strconv.FormatFloat(level*100, 'f', -1, 64) + "%"
+}
+
+// confidence returns the configured confidence level, 95% by default
+func (c Config) confidence() float64 {
+	if c.Confidence <= 0 {
+		return DefaultConfidence
+	}
+	return c.Confidence
+}
+
+// BootstrapConfidenceInterval returns the percentile bootstrap confidence
+// interval of the chosen statistic: the statistic is recomputed on samples
+// drawn with replacement from durations, and the interval spans the middle
+// level of those estimates. Unlike the t-interval it assumes nothing about the
+// distribution of the durations, which are often skewed.
+func BootstrapConfidenceInterval(durations []float64, statistic CIStatistic, level float64) (low, high float64) {
+	n := len(durations)
+	if n == 0 {
+		return 0, 0
+	}
+
+	rng := rand.New(rand.NewPCG(bootstrapSeed, uint64(n)))
+	sample := make([]float64, n)
+	estimates := make([]float64, bootstrapResamples)
+	for i := range estimates {
+		for j := range sample {
+			sample[j] = durations[rng.IntN(n)]
+		}
+		if statistic == CIStatisticMedian {
+			sort.Float64s(sample)
+			estimates[i] = percentile(sample, 50)
+			continue
+		}
+		sum := 0.0
+		for _, d := range sample {
+			sum += d
+		}
+		estimates[i] = sum / float64(n)
+	}
+
+	sort.Float64s(estimates)
+	tail := (1 - level) / 2 * 100
+	return percentile(estimates, tail), percentile(estimates, 100-tail)
+}
+
+// calculateConfidenceIntervals fills in the confidence intervals of the mean
+// and median of durations at level
+func (s *Statistics) calculateConfidenceIntervals(durations []float64, level float64) {
+	s.Confidence = level
+	if len(durations) < 2 {
+		return
+	}
+
+	s.MeanCI.Low, s.MeanCI.High = MeanConfidenceInterval(durations, level)
+	s.MeanBootstrapCI.Low, s.MeanBootstrapCI.High = BootstrapConfidenceInterval(durations, CIStatisticMean, level)
+	s.MedianCI.Low, s.MedianCI.High = BootstrapConfidenceInterval(durations, CIStatisticMedian, level)
+}
@@ -0,0 +1,128 @@
+package benchmark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseConfidence(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		err   bool
+	}{
+		{"95%", 0.95, false},
+		{"99.9%", 0.999, false},
+		{"0.9", 0.9, false},
+		{"90", 0, true},
+		{"1", 0, true},
+		{"100%", 0, true},
+		{"0", 0, true},
+		{"high", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseConfidence(tt.value)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseConfidence(%q) error = %v, want error %t", tt.value, err, tt.err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("ParseConfidence(%q) = %g, want %g", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseTargetCI(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		err   bool
+	}{
+		{"", 0, false},
+		{"2%", 0.02, false},
+		{"0.02", 0.02, false},
+		{"2", 0, true},
+		{"0%", 0, true},
+		{"tight", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTargetCI(tt.value)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseTargetCI(%q) error = %v, want error %t", tt.value, err, tt.err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("ParseTargetCI(%q) = %g, want %g", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMeanConfidenceInterval(t *testing.T) {
+	// mean ± t(1-(1-level)/2, n-1) · s/√n
+	tests := []struct {
+		durations []float64
+		level     float64
+		low, high float64
+	}{
+		{[]float64{1, 2, 3, 4, 5}, 0.95, 3 - 2.776445105197799*math.Sqrt(0.5), 3 + 2.776445105197799*math.Sqrt(0.5)},
+		{[]float64{10, 12}, 0.95, 11 - 12.706204736174698, 11 + 12.706204736174698},
+		{[]float64{2, 2, 2}, 0.99, 2, 2},
+		{[]float64{7}, 0.95, 7, 7},
+	}
+	for _, tt := range tests {
+		low, high := MeanConfidenceInterval(tt.durations, tt.level)
+		if math.Abs(low-tt.low) > 1e-6 || math.Abs(high-tt.high) > 1e-6 {
+			t.Errorf("MeanConfidenceInterval(%v, %g) = %.6f–%.6f, want %.6f–%.6f", tt.durations, tt.level, low, high, tt.low, tt.high)
+		}
+	}
+}
+
+func TestBootstrapConfidenceInterval(t *testing.T) {
+	durations := make([]float64, 20)
+	for i := range durations {
+		durations[i] = float64(i + 1)
+	}
+
+	// For a symmetric sample the percentile bootstrap of the mean is close
+	// to the normal interval with the plug-in standard error
+	stats := CalculateStatistics(durations)
+	n := float64(len(durations))
+	half := 1.959963984540054 * stats.StdDev * math.Sqrt((n-1)/n) / math.Sqrt(n)
+	low, high := BootstrapConfidenceInterval(durations, CIStatisticMean, 0.95)
+	if math.Abs(low-(stats.Mean-half)) > 0.15 || math.Abs(high-(stats.Mean+half)) > 0.15 {
+		t.Errorf("bootstrap mean CI = %.3f–%.3f, want about %.3f–%.3f", low, high, stats.Mean-half, stats.Mean+half)
+	}
+
+	// The generator is seeded, so the interval is reproducible
+	if low2, high2 := BootstrapConfidenceInterval(durations, CIStatisticMean, 0.95); low2 != low || high2 != high {
+		t.Errorf("bootstrap mean CI is not reproducible: %g–%g, then %g–%g", low, high, low2, high2)
+	}
+
+	// The median's interval lies within the sample and contains the median
+	low, high = BootstrapConfidenceInterval(durations, CIStatisticMedian, 0.95)
+	if low < 1 || high > 20 || low > stats.Median || high < stats.Median {
+		t.Errorf("bootstrap median CI = %g–%g, want an interval in 1–20 containing %g", low, high, stats.Median)
+	}
+
+	// A constant sample has a zero-width interval
+	if low, high := BootstrapConfidenceInterval([]float64{4, 4, 4, 4}, CIStatisticMedian, 0.95); low != 4 || high != 4 {
+		t.Errorf("bootstrap CI of a constant sample = %g–%g, want 4–4", low, high)
+	}
+}
+
+func TestRelativeCI(t *testing.T) {
+	durations := []float64{1, 2, 3, 4, 5}
+	if got, want := RelativeCI(durations, CIStatisticMean, 0.95), 2.776445105197799*math.Sqrt(0.5)/3; math.Abs(got-want) > 1e-6 {
+		t.Errorf("RelativeCI(mean) = %g, want %g", got, want)
+	}
+
+	// The median's relative CI is the reported bootstrap interval around the median
+	low, high := BootstrapConfidenceInterval(durations, CIStatisticMedian, 0.95)
+	if got, want := RelativeCI(durations, CIStatisticMedian, 0.95), (high-low)/2/3; got != want {
+		t.Errorf("RelativeCI(median) = %g, want %g", got, want)
+	}
+
+	if got := RelativeCI([]float64{1}, CIStatisticMean, 0.95); !math.IsInf(got, 1) {
+		t.Errorf("RelativeCI of one duration = %g, want +Inf", got)
+	}
+}
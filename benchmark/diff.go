@@ -0,0 +1,160 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+)
+
+// Verdict is the plain-language outcome of comparing two benchmarks
+type Verdict string
+
+const (
+	VerdictFaster       Verdict = "faster"                // The candidate is significantly faster than the baseline
+	VerdictSlower       Verdict = "slower"                // The candidate is significantly slower than the baseline
+	VerdictNoChange     Verdict = "no significant change" // The difference may well be noise
+	VerdictInsufficient Verdict = "insufficient data"     // Either side has fewer than two successful runs
+)
+
+// SampleSummary describes the durations of one side of a comparison
+type SampleSummary struct {
+	Name   string  // What was measured, e.g. the result file and command
+	N      int     // Number of durations
+	Mean   float64 // Mean duration in seconds
+	Median float64 // Median duration in seconds
+	StdDev float64 // Sample standard deviation in seconds
+}
+
+// Diff compares a candidate benchmark against a baseline
+type Diff struct {
+	Baseline   SampleSummary
+	Candidate  SampleSummary
+	Confidence float64 // Confidence level of DifferenceCI; 1 - Confidence is the significance level of the tests
+
+	Difference         float64            // Candidate mean - baseline mean in seconds (negative = faster)
+	RelativeDifference float64            // Difference / baseline mean
+	DifferenceCI       ConfidenceInterval // Confidence interval of Difference, from Welch's t-test
+	EffectSize         float64            // Cohen's d: Difference in units of the pooled standard deviation
+
+	// Welch's t-test on the means
+	WelchT  float64
+	WelchDF float64
+	WelchP  float64
+
+	// Mann–Whitney U test on the distributions (only with per-run durations)
+	MannWhitney  bool
+	MannWhitneyU float64
+	MannWhitneyP float64
+
+	Verdict Verdict
+}
+
+// DiffDurations compares the per-run durations of a candidate against a
+// baseline with Welch's t-test and the Mann–Whitney U test
+func DiffDurations(baseline, candidate []float64, level float64) Diff {
+	diff := DiffSummaries(summarizeDurations(baseline), summarizeDurations(candidate), level)
+	if diff.Verdict != VerdictInsufficient {
+		diff.MannWhitney = true
+		diff.MannWhitneyU, diff.MannWhitneyP = mannWhitneyUTest(baseline, candidate)
+		diff.Verdict = diff.verdict()
+	}
+	return diff
+}
+
+// DiffSummaries compares a candidate against a baseline known only by their
+// summary statistics, so only Welch's t-test is available
+func DiffSummaries(baseline, candidate SampleSummary, level float64) Diff {
+	diff := Diff{
+		Baseline:   baseline,
+		Candidate:  candidate,
+		Confidence: level,
+		Difference: candidate.Mean - baseline.Mean,
+		Verdict:    VerdictInsufficient,
+	}
+	if baseline.Mean > 0 {
+		diff.RelativeDifference = diff.Difference / baseline.Mean
+	}
+	if baseline.N < 2 || candidate.N < 2 {
+		return diff
+	}
+
+	var stdErr float64
+	diff.WelchT, diff.WelchDF, diff.WelchP, stdErr = welchTTest(baseline, candidate)
+	half := studentTQuantile(1-(1-level)/2, diff.WelchDF) * stdErr
+	diff.DifferenceCI = ConfidenceInterval{Low: diff.Difference - half, High: diff.Difference + half}
+	diff.EffectSize = cohensD(baseline, candidate)
+	diff.Verdict = diff.verdict()
+	return diff
+}
+
+// Significant reports whether every test that ran rejects "no difference" at
+// the significance level 1 - Confidence
+func (d Diff) Significant() bool {
+	alpha := 1 - d.Confidence
+	if d.Baseline.N < 2 || d.Candidate.N < 2 || d.WelchP >= alpha {
+		return false
+	}
+	return !d.MannWhitney || d.MannWhitneyP < alpha
+}
+
+// verdict derives the verdict from the tests
+func (d Diff) verdict() Verdict {
+	switch {
+	case !d.Significant():
+		return VerdictNoChange
+	case d.Difference < 0:
+		return VerdictFaster
+	default:
+		return VerdictSlower
+	}
+}
+
+// EffectMagnitude names the size of the effect by Cohen's conventions
+func (d Diff) EffectMagnitude() string {
+	switch size := math.Abs(d.EffectSize); {
+	case size < 0.2:
+		return "negligible"
+	case size < 0.5:
+		return "small"
+	case size < 0.8:
+		return "medium"
+	default:
+		return "large"
+	}
+}
+
+// Summary describes the outcome in a sentence, e.g. "8.6% faster (95% confidence)"
+func (d Diff) Summary() string {
+	switch d.Verdict {
+	case VerdictFaster, VerdictSlower:
+		return fmt.Sprintf("%.1f%% %s (%s confidence)", math.Abs(d.RelativeDifference)*100, d.Verdict, FormatConfidence(d.Confidence))
+	case VerdictNoChange:
+		return fmt.Sprintf("no significant change (%+.1f%%, not significant at %s confidence)", d.RelativeDifference*100, FormatConfidence(d.Confidence))
+	default:
+		return "insufficient data: both sides need at least 2 successful runs"
+	}
+}
+
+// summarizeDurations returns the summary statistics of durations
+func summarizeDurations(durations []float64) SampleSummary {
+	stats := CalculateStatistics(durations)
+	return SampleSummary{N: stats.N, Mean: stats.Mean, Median: stats.Median, StdDev: stats.StdDev}
+}
+
+// LoadDurations reads a benchmark JSON result and returns the label of its
+// command and the durations its statistics were computed from: the successful
+// runs, without the shell's spawn time when calibrated and without outliers
+// when they were trimmed
+func LoadDurations(path string) (label string, durations []float64, err error) {
+	result, err := LoadResult(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	durations = measuredDurations(result.Runs, result.ShellOverhead, result.Stats.Trimmed)
+
+	label = result.Config.CommandName
+	if label == "" {
+		label = result.Config.Command
+	}
+	return label, durations, nil
+}
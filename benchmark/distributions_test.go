@@ -0,0 +1,89 @@
+package benchmark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentTQuantile(t *testing.T) {
+	// Reference values from t tables (R's qt)
+	tests := []struct {
+		p, df float64
+		want  float64
+	}{
+		{0.975, 1, 12.706204736174698},
+		{0.975, 2, 4.302652729911275},
+		{0.975, 4, 2.776445105197799},
+		{0.975, 5, 2.570581835636314},
+		{0.975, 30, 2.042272456301238},
+		{0.95, 5, 2.015048372669157},
+		{0.995, 30, 2.749995653567},
+		{0.5, 7, 0},
+		{0.025, 5, -2.570581835636314},
+	}
+	for _, tt := range tests {
+		if got := studentTQuantile(tt.p, tt.df); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("studentTQuantile(%g, %g) = %.9f, want %.9f", tt.p, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestStudentTCDF(t *testing.T) {
+	tests := []struct {
+		t, df float64
+		want  float64
+	}{
+		{0, 3, 0.5},
+		{1, 1, 0.75}, // Cauchy: 1/2 + atan(1)/π
+		{-1, 1, 0.25},
+		{2.570581835636314, 5, 0.975},
+		{2.042272456301238, 30, 0.975},
+		{math.Inf(1), 5, 1},
+		{math.Inf(-1), 5, 0},
+	}
+	for _, tt := range tests {
+		if got := studentTCDF(tt.t, tt.df); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("studentTCDF(%g, %g) = %.12f, want %.12f", tt.t, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	tests := []struct {
+		p, want float64
+	}{
+		{0.5, 0},
+		{0.975, 1.959963984540054},
+		{0.995, 2.5758293035489004},
+		{0.05, -1.6448536269514729},
+	}
+	for _, tt := range tests {
+		if got := normalQuantile(tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("normalQuantile(%g) = %.12f, want %.12f", tt.p, got, tt.want)
+		}
+		if got := normalCDF(tt.want); math.Abs(got-tt.p) > 1e-9 {
+			t.Errorf("normalCDF(%g) = %.12f, want %.12f", tt.want, got, tt.p)
+		}
+	}
+}
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	tests := []struct {
+		a, b, x float64
+		want    float64
+	}{
+		{1, 1, 0.3, 0.3},             // Uniform distribution
+		{2, 1, 0.5, 0.25},            // x^a when b = 1
+		{1, 3, 0.2, 1 - 0.8*0.8*0.8}, // 1 - (1-x)^b when a = 1
+		{0.5, 0.5, 0.5, 0.5},         // Symmetric around 1/2
+		{2, 3, 0.4, 0.5248},          // Binomial tail P(X >= 2), X ~ Bin(4, 0.4)
+		{5, 5, 0.9, 0.99910908},      // Binomial tail P(X >= 5), X ~ Bin(9, 0.9)
+		{3, 2, 0, 0},
+		{3, 2, 1, 1},
+	}
+	for _, tt := range tests {
+		if got := regularizedIncompleteBeta(tt.a, tt.b, tt.x); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("regularizedIncompleteBeta(%g, %g, %g) = %.12f, want %.12f", tt.a, tt.b, tt.x, got, tt.want)
+		}
+	}
+}
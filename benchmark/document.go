@@ -0,0 +1,813 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON result documents written by this
+// version of caliper. Documents without a schemaVersion field are version 0,
+// the format before it was versioned. Loading a document migrates it to the
+// current version; a document from a newer caliper is rejected.
+//
+// Version history:
+//
+//	0  unversioned: a single "warmupRun" instead of "warmupRuns", and only
+//	   some of the statistics (the others are recomputed from the runs)
+//	1  schemaVersion field, typed documents (ResultDocument). The comparison,
+//	   scan and diff documents, which caliper writes but never reads back,
+//	   only gained the schemaVersion field.
+const SchemaVersion = 1
+
+// ResultDocument is the JSON document of a single benchmark result, as saved by
+// SaveJSON and read by LoadResult. Durations are in seconds unless noted.
+type ResultDocument struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	Config        ConfigDocument        `json:"config"`
+	Summary       SummaryDocument       `json:"summary"`
+	Statistics    StatisticsDocument    `json:"statistics"`
+	ResourceUsage ResourceUsageDocument `json:"resourceUsage"`
+	Environment   map[string]string     `json:"environment"` // Effective environment, secret values redacted
+	Runs          []RunResult           `json:"runs"`        // Measured runs; Duration and resource times in nanoseconds
+	HookFailures  []HookFailure         `json:"hookFailures"`
+	Host          HostDocument          `json:"host"`
+	WarmupRuns    []WarmupRunDocument   `json:"warmupRuns,omitempty"`
+}
+
+// ConfigDocument is the configuration of a benchmark
+type ConfigDocument struct {
+	Command           string             `json:"command"`
+	CommandName       string             `json:"commandName"`
+	Parameter         *ParameterDocument `json:"parameter,omitempty"` // Only when scanning a parameter
+	Runs              int                `json:"runs"`
+	Name              string             `json:"name"`
+	OutputDir         string             `json:"outputDir"`
+	Timeout           float64            `json:"timeout"` // Per-run timeout (0 = none)
+	ExpectedExitCodes []int              `json:"expectedExitCodes"`
+	IgnoreFailure     bool               `json:"ignoreFailure"`
+	Retries           int                `json:"retries"`
+	RetryOnExitCodes  []int              `json:"retryOnExitCodes"`
+	Shell             string             `json:"shell"`
+	Calibrate         bool               `json:"calibrate"`
+	Env               map[string]string  `json:"env"` // Overrides from --env and --env-file, secret values redacted
+	CleanEnv          bool               `json:"cleanEnv"`
+	Cwd               string             `json:"cwd"`
+	Confidence        float64            `json:"confidence"`
+	Percentiles       []float64          `json:"percentiles"`
+	RequireQuietHost  bool               `json:"requireQuietHost"`
+	Warmup            WarmupDocument     `json:"warmup"`
+	Adaptive          AdaptiveDocument   `json:"adaptive"`
+	Hooks             HooksDocument      `json:"hooks"`
+	Service           ServiceDocument    `json:"service"`
+}
+
+// ParameterDocument is the scanned parameter of a benchmark
+type ParameterDocument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// WarmupDocument is the warm-up configuration
+type WarmupDocument struct {
+	Skip      bool    `json:"skip"`
+	Runs      int     `json:"runs"`
+	Auto      bool    `json:"auto"`
+	Tolerance float64 `json:"tolerance"`
+	MaxRuns   int     `json:"maxRuns"`
+}
+
+// AdaptiveDocument is the configuration of the adaptive run count
+type AdaptiveDocument struct {
+	TargetCI    float64     `json:"targetCI"` // 0 with a fixed run count
+	CIStatistic CIStatistic `json:"ciStatistic"`
+	MinRuns     int         `json:"minRuns"`
+	MaxRuns     int         `json:"maxRuns"`
+	MaxTime     float64     `json:"maxTime"`
+}
+
+// HooksDocument is the hook configuration
+type HooksDocument struct {
+	Setup    string `json:"setup"`
+	Prepare  string `json:"prepare"`
+	Conclude string `json:"conclude"`
+	Cleanup  string `json:"cleanup"`
+}
+
+// ServiceDocument is the configuration of the service under test
+type ServiceDocument struct {
+	Command        string            `json:"command"`
+	MeasureStartup bool              `json:"measureStartup"`
+	Readiness      ReadinessDocument `json:"readiness"`
+}
+
+// ReadinessDocument is the readiness probe of the service under test
+type ReadinessDocument struct {
+	TCP     string  `json:"tcp"`
+	HTTP    string  `json:"http"`
+	Output  string  `json:"output"`
+	Timeout float64 `json:"timeout"`
+}
+
+// SummaryDocument summarizes the runs of a benchmark
+type SummaryDocument struct {
+	TotalRuns     int               `json:"totalRuns"`
+	Successful    int               `json:"successful"`
+	Failed        int               `json:"failed"`
+	TimedOut      int               `json:"timedOut"`
+	HookFailures  int               `json:"hookFailures"`
+	SuccessRate   float64           `json:"successRate"` // Percentage
+	Flakiness     float64           `json:"flakiness"`   // Percentage
+	RetriedRuns   int               `json:"retriedRuns"`
+	Retries       int               `json:"retries"`
+	StartTime     string            `json:"startTime"` // RFC 3339
+	EndTime       string            `json:"endTime"`   // RFC 3339
+	TotalDuration float64           `json:"totalDuration"`
+	StopReason    StopReason        `json:"stopReason"`
+	Interrupted   bool              `json:"interrupted"`
+	Sessions      []SessionDocument `json:"sessions"`
+	RelativeCI    float64           `json:"relativeCI"`
+	ShellOverhead float64           `json:"shellOverhead"`
+	ServiceReady  float64           `json:"serviceReady"`
+	WarmupRuns    int               `json:"warmupRuns"`
+	WarmupStable  bool              `json:"warmupStable"`
+	ColdFirstRun  bool              `json:"coldFirstRun"`
+}
+
+// SessionDocument is an invocation of caliper that contributed runs
+type SessionDocument struct {
+	Number    int    `json:"number"`
+	StartTime string `json:"startTime"` // RFC 3339
+	Runs      int    `json:"runs"`
+}
+
+// StatisticsDocument holds the statistics of the measured durations
+type StatisticsDocument struct {
+	N                   int                         `json:"n"`
+	Mean                float64                     `json:"mean"`
+	Median              float64                     `json:"median"`
+	StdDev              float64                     `json:"stdDev"`
+	CV                  float64                     `json:"cv"`
+	MAD                 float64                     `json:"mad"`
+	Min                 float64                     `json:"min"`
+	Max                 float64                     `json:"max"`
+	P90                 float64                     `json:"p90"`
+	P95                 float64                     `json:"p95"`
+	Percentiles         []Percentile                `json:"percentiles"`
+	Estimators          EstimatorsDocument          `json:"estimators"`
+	Outliers            OutliersDocument            `json:"outliers"`
+	ConfidenceIntervals ConfidenceIntervalsDocument `json:"confidenceIntervals"`
+	Trend               TrendDocument               `json:"trend"`
+}
+
+// EstimatorsDocument names the estimators of the statistics
+type EstimatorsDocument struct {
+	StdDev     string `json:"stdDev"`
+	MAD        string `json:"mad"`
+	Percentile string `json:"percentile"`
+}
+
+// OutliersDocument describes the outlier detection
+type OutliersDocument struct {
+	Method     OutlierMethod `json:"method"`
+	Count      int           `json:"count"`
+	LowerFence float64       `json:"lowerFence"`
+	UpperFence float64       `json:"upperFence"`
+	Trimmed    bool          `json:"trimmed"`
+}
+
+// ConfidenceIntervalsDocument holds the confidence intervals of the mean and median
+type ConfidenceIntervalsDocument struct {
+	Level              float64            `json:"level"`
+	BootstrapResamples int                `json:"bootstrapResamples,omitempty"` // Not recorded in matrix summaries
+	MeanT              ConfidenceInterval `json:"meanT"`
+	MeanBootstrap      ConfidenceInterval `json:"meanBootstrap"`
+	MedianBootstrap    ConfidenceInterval `json:"medianBootstrap"`
+}
+
+// TrendDocument is the trend of the durations and whether it triggers the drift warning
+type TrendDocument struct {
+	Trend
+	Drifting bool `json:"drifting"`
+}
+
+// ResourceUsageDocument is the mean resource usage of the successful runs
+type ResourceUsageDocument struct {
+	MeanUserTime               float64 `json:"meanUserTime"`
+	MeanSystemTime             float64 `json:"meanSystemTime"`
+	MeanCPUTime                float64 `json:"meanCpuTime"`
+	CPUUtilization             float64 `json:"cpuUtilization"`
+	MeanMaxRSS                 float64 `json:"meanMaxRss"` // Bytes
+	PeakMaxRSS                 int64   `json:"peakMaxRss"` // Bytes
+	MeanVoluntaryCtxSwitches   float64 `json:"meanVoluntaryCtxSwitches"`
+	MeanInvoluntaryCtxSwitches float64 `json:"meanInvoluntaryCtxSwitches"`
+	MeanMinorPageFaults        float64 `json:"meanMinorPageFaults"`
+	MeanMajorPageFaults        float64 `json:"meanMajorPageFaults"`
+}
+
+// HostDocument holds the host noise samples
+type HostDocument struct {
+	Samples      []HostSample `json:"samples"`
+	NoisySamples int          `json:"noisySamples"`
+}
+
+// WarmupRunDocument is a warm-up run
+type WarmupRunDocument struct {
+	WarmupNumber int           `json:"warmupNumber"`
+	Session      int           `json:"session"`
+	Attempt      int           `json:"attempt"`
+	Duration     float64       `json:"duration"`
+	Success      bool          `json:"success"`
+	Status       RunStatus     `json:"status"`
+	Error        string        `json:"error"`
+	ExitCode     int           `json:"exitCode"`
+	StderrTail   string        `json:"stderrTail"`
+	StdoutLog    string        `json:"stdoutLog"`
+	StderrLog    string        `json:"stderrLog"`
+	Resources    ResourceUsage `json:"resources"`
+}
+
+// NewResultDocument converts a benchmark result to its JSON document
+func NewResultDocument(result *Result) ResultDocument {
+	config := result.Config
+	stats := result.Stats
+	res := stats.Resources
+
+	doc := ResultDocument{
+		SchemaVersion: SchemaVersion,
+		Config: ConfigDocument{
+			Command:           config.Command,
+			CommandName:       config.CommandName,
+			Runs:              config.Runs,
+			Name:              config.Name,
+			OutputDir:         config.OutputDir,
+			Timeout:           config.Timeout.Seconds(),
+			ExpectedExitCodes: config.ExpectedExitCodes,
+			IgnoreFailure:     config.IgnoreFailure,
+			Retries:           config.Retries,
+			RetryOnExitCodes:  config.RetryOnExitCodes,
+			Shell:             config.ShellName(),
+			Calibrate:         config.Calibrate,
+			Env:               config.RedactedEnv(),
+			CleanEnv:          config.CleanEnv,
+			Cwd:               config.Dir,
+			Confidence:        config.confidence(),
+			Percentiles:       config.Percentiles,
+			RequireQuietHost:  config.RequireQuietHost,
+			Warmup: WarmupDocument{
+				Skip:      config.SkipWarmup,
+				Runs:      config.Warmup,
+				Auto:      config.WarmupAuto,
+				Tolerance: config.WarmupTolerance,
+				MaxRuns:   config.MaxWarmupRuns,
+			},
+			Adaptive: AdaptiveDocument{
+				TargetCI:    config.TargetCI,
+				CIStatistic: config.CIStatistic,
+				MinRuns:     config.MinRuns,
+				MaxRuns:     config.MaxRuns,
+				MaxTime:     config.MaxTime.Seconds(),
+			},
+			Hooks: HooksDocument{
+				Setup:    config.Setup,
+				Prepare:  config.Prepare,
+				Conclude: config.Conclude,
+				Cleanup:  config.Cleanup,
+			},
+			Service: ServiceDocument{
+				Command:        config.Service,
+				MeasureStartup: config.MeasureStartup,
+				Readiness: ReadinessDocument{
+					TCP:     config.Readiness.TCP,
+					HTTP:    config.Readiness.HTTP,
+					Output:  config.Readiness.Output,
+					Timeout: config.Readiness.Timeout.Seconds(),
+				},
+			},
+		},
+		Summary: SummaryDocument{
+			TotalRuns:     len(result.Runs),
+			Successful:    result.SuccessfulRuns(),
+			Failed:        len(result.Runs) - result.SuccessfulRuns(),
+			TimedOut:      result.TimedOutRuns(),
+			HookFailures:  len(result.HookFailures),
+			SuccessRate:   result.SuccessRate,
+			Flakiness:     result.Flakiness,
+			RetriedRuns:   len(result.RetriedRuns()),
+			Retries:       result.Retries(),
+			StartTime:     result.StartTime.Format(time.RFC3339),
+			EndTime:       result.EndTime.Format(time.RFC3339),
+			TotalDuration: result.TotalDuration.Seconds(),
+			StopReason:    result.StopReason,
+			Interrupted:   result.Interrupted,
+			Sessions:      sessionDocuments(result.Sessions),
+			RelativeCI:    result.RelativeCI,
+			ShellOverhead: result.ShellOverhead.Seconds(),
+			ServiceReady:  result.ServiceReady.Seconds(),
+			WarmupRuns:    len(result.WarmupRuns),
+			WarmupStable:  result.WarmupStable,
+			ColdFirstRun:  result.ColdFirstRun,
+		},
+		Statistics: StatisticsDocument{
+			N:           stats.N,
+			Mean:        stats.Mean,
+			Median:      stats.Median,
+			StdDev:      stats.StdDev,
+			CV:          stats.CV,
+			MAD:         stats.MAD,
+			Min:         stats.Min,
+			Max:         stats.Max,
+			P90:         stats.P90,
+			P95:         stats.P95,
+			Percentiles: percentilesJSON(stats.Percentiles),
+			Estimators: EstimatorsDocument{
+				StdDev:     StdDevEstimator,
+				MAD:        MADEstimator,
+				Percentile: PercentileEstimator,
+			},
+			Outliers: OutliersDocument{
+				Method:     stats.OutlierMethod,
+				Count:      stats.Outliers,
+				LowerFence: stats.LowerFence,
+				UpperFence: stats.UpperFence,
+				Trimmed:    stats.Trimmed,
+			},
+			ConfidenceIntervals: ConfidenceIntervalsDocument{
+				Level:              stats.Confidence,
+				BootstrapResamples: bootstrapResamples,
+				MeanT:              stats.MeanCI,
+				MeanBootstrap:      stats.MeanBootstrapCI,
+				MedianBootstrap:    stats.MedianCI,
+			},
+			Trend: TrendDocument{Trend: stats.Trend, Drifting: stats.Trend.Drifting()},
+		},
+		ResourceUsage: ResourceUsageDocument{
+			MeanUserTime:               res.MeanUserTime,
+			MeanSystemTime:             res.MeanSystemTime,
+			MeanCPUTime:                res.MeanCPUTime,
+			CPUUtilization:             res.CPUUtilization,
+			MeanMaxRSS:                 res.MeanMaxRSS,
+			PeakMaxRSS:                 res.PeakMaxRSS,
+			MeanVoluntaryCtxSwitches:   res.MeanVoluntaryCtxSwitches,
+			MeanInvoluntaryCtxSwitches: res.MeanInvoluntaryCtxSwitches,
+			MeanMinorPageFaults:        res.MeanMinorPageFaults,
+			MeanMajorPageFaults:        res.MeanMajorPageFaults,
+		},
+		Environment:  result.Environment,
+		Runs:         result.Runs,
+		HookFailures: result.HookFailures,
+		Host: HostDocument{
+			Samples:      hostSamplesJSON(result.HostSamples),
+			NoisySamples: len(result.NoisyHostSamples()),
+		},
+	}
+
+	if config.Parameter.Name != "" {
+		doc.Config.Parameter = &ParameterDocument{Name: config.Parameter.Name, Value: config.Parameter.Value}
+	}
+
+	for i, run := range result.WarmupRuns {
+		doc.WarmupRuns = append(doc.WarmupRuns, WarmupRunDocument{
+			WarmupNumber: i + 1,
+			Session:      run.Session,
+			Attempt:      run.Attempt,
+			Duration:     run.Duration.Seconds(),
+			Success:      run.Success,
+			Status:       run.Status,
+			Error:        run.Error,
+			ExitCode:     run.ExitCode,
+			StderrTail:   run.StderrTail,
+			StdoutLog:    run.StdoutLog,
+			StderrLog:    run.StderrLog,
+			Resources:    run.Resources,
+		})
+	}
+
+	return doc
+}
+
+// Result converts the document back to a benchmark result. The configuration
+// only holds what the document records: the command, hooks and reporting
+// options, but e.g. not the log settings.
+func (d ResultDocument) Result() *Result {
+	c := d.Config
+	config := Config{
+		Command:           c.Command,
+		CommandName:       c.CommandName,
+		Runs:              c.Runs,
+		Name:              c.Name,
+		OutputDir:         c.OutputDir,
+		SkipWarmup:        c.Warmup.Skip,
+		Warmup:            c.Warmup.Runs,
+		WarmupAuto:        c.Warmup.Auto,
+		WarmupTolerance:   c.Warmup.Tolerance,
+		MaxWarmupRuns:     c.Warmup.MaxRuns,
+		Shell:             c.Shell,
+		Calibrate:         c.Calibrate,
+		Timeout:           seconds(c.Timeout),
+		Env:               envEntries(c.Env),
+		CleanEnv:          c.CleanEnv,
+		Dir:               c.Cwd,
+		Setup:             c.Hooks.Setup,
+		Prepare:           c.Hooks.Prepare,
+		Conclude:          c.Hooks.Conclude,
+		Cleanup:           c.Hooks.Cleanup,
+		Service:           c.Service.Command,
+		MeasureStartup:    c.Service.MeasureStartup,
+		ExpectedExitCodes: c.ExpectedExitCodes,
+		IgnoreFailure:     c.IgnoreFailure,
+		Retries:           c.Retries,
+		RetryOnExitCodes:  c.RetryOnExitCodes,
+		OutlierMethod:     d.Statistics.Outliers.Method,
+		TrimOutliers:      d.Statistics.Outliers.Trimmed,
+		Confidence:        c.Confidence,
+		Percentiles:       c.Percentiles,
+		RequireQuietHost:  c.RequireQuietHost,
+		TargetCI:          c.Adaptive.TargetCI,
+		CIStatistic:       c.Adaptive.CIStatistic,
+		MinRuns:           c.Adaptive.MinRuns,
+		MaxRuns:           c.Adaptive.MaxRuns,
+		MaxTime:           seconds(c.Adaptive.MaxTime),
+		Readiness: ReadinessProbe{
+			TCP:     c.Service.Readiness.TCP,
+			HTTP:    c.Service.Readiness.HTTP,
+			Output:  c.Service.Readiness.Output,
+			Timeout: seconds(c.Service.Readiness.Timeout),
+		},
+	}
+	if c.Parameter != nil {
+		config.Parameter = Parameter{Name: c.Parameter.Name, Value: c.Parameter.Value}
+	}
+
+	s := d.Statistics
+	res := d.ResourceUsage
+	result := &Result{
+		Config:        config,
+		WarmupStable:  d.Summary.WarmupStable,
+		ColdFirstRun:  d.Summary.ColdFirstRun,
+		Runs:          d.Runs,
+		HookFailures:  d.HookFailures,
+		SuccessRate:   d.Summary.SuccessRate,
+		Flakiness:     d.Summary.Flakiness,
+		StopReason:    d.Summary.StopReason,
+		ShellOverhead: seconds(d.Summary.ShellOverhead),
+		RelativeCI:    d.Summary.RelativeCI,
+		Environment:   d.Environment,
+		Interrupted:   d.Summary.Interrupted,
+		ServiceReady:  seconds(d.Summary.ServiceReady),
+		HostSamples:   d.Host.Samples,
+		TotalDuration: seconds(d.Summary.TotalDuration),
+		Stats: Statistics{
+			N:               s.N,
+			Mean:            s.Mean,
+			Median:          s.Median,
+			StdDev:          s.StdDev,
+			CV:              s.CV,
+			MAD:             s.MAD,
+			Min:             s.Min,
+			Max:             s.Max,
+			P90:             s.P90,
+			P95:             s.P95,
+			Percentiles:     s.Percentiles,
+			Trend:           s.Trend.Trend,
+			OutlierMethod:   s.Outliers.Method,
+			Outliers:        s.Outliers.Count,
+			LowerFence:      s.Outliers.LowerFence,
+			UpperFence:      s.Outliers.UpperFence,
+			Trimmed:         s.Outliers.Trimmed,
+			Confidence:      s.ConfidenceIntervals.Level,
+			MeanCI:          s.ConfidenceIntervals.MeanT,
+			MeanBootstrapCI: s.ConfidenceIntervals.MeanBootstrap,
+			MedianCI:        s.ConfidenceIntervals.MedianBootstrap,
+			Resources: ResourceStatistics{
+				MeanUserTime:               res.MeanUserTime,
+				MeanSystemTime:             res.MeanSystemTime,
+				MeanCPUTime:                res.MeanCPUTime,
+				CPUUtilization:             res.CPUUtilization,
+				MeanMaxRSS:                 res.MeanMaxRSS,
+				PeakMaxRSS:                 res.PeakMaxRSS,
+				MeanVoluntaryCtxSwitches:   res.MeanVoluntaryCtxSwitches,
+				MeanInvoluntaryCtxSwitches: res.MeanInvoluntaryCtxSwitches,
+				MeanMinorPageFaults:        res.MeanMinorPageFaults,
+				MeanMajorPageFaults:        res.MeanMajorPageFaults,
+			},
+		},
+	}
+	// Times that fail to parse stay zero
+	result.StartTime, _ = time.Parse(time.RFC3339, d.Summary.StartTime)
+	result.EndTime, _ = time.Parse(time.RFC3339, d.Summary.EndTime)
+
+	for _, session := range d.Summary.Sessions {
+		startTime, _ := time.Parse(time.RFC3339, session.StartTime)
+		result.Sessions = append(result.Sessions, Session{Number: session.Number, StartTime: startTime, Runs: session.Runs})
+	}
+
+	for _, run := range d.WarmupRuns {
+		result.WarmupRuns = append(result.WarmupRuns, RunResult{
+			Session:    run.Session,
+			Attempt:    run.Attempt,
+			Duration:   seconds(run.Duration),
+			Success:    run.Success,
+			Status:     run.Status,
+			Error:      run.Error,
+			ExitCode:   run.ExitCode,
+			StderrTail: run.StderrTail,
+			StdoutLog:  run.StdoutLog,
+			StderrLog:  run.StderrLog,
+			Resources:  run.Resources,
+		})
+	}
+
+	return result
+}
+
+// ComparisonDocument is the JSON document of several commands benchmarked in
+// one invocation, as saved by SaveComparisonJSON. Durations are in seconds.
+type ComparisonDocument struct {
+	SchemaVersion int                       `json:"schemaVersion"`
+	Commands      []ComparedCommandDocument `json:"commands"`
+	Fastest       string                    `json:"fastest,omitempty"` // Omitted when no command succeeded
+	Summary       []string                  `json:"summary"`
+}
+
+// ComparedCommandDocument is the statistics of one command of a comparison
+// and its speed relative to the fastest one
+type ComparedCommandDocument struct {
+	Name                string  `json:"name"`
+	Command             string  `json:"command"`
+	SuccessRate         float64 `json:"successRate"` // Percentage
+	N                   int     `json:"n"`
+	Mean                float64 `json:"mean"`
+	Median              float64 `json:"median"`
+	StdDev              float64 `json:"stdDev"`
+	Min                 float64 `json:"min"`
+	Max                 float64 `json:"max"`
+	Relative            float64 `json:"relative"` // Mean / fastest mean (0 = no successful runs)
+	RelativeUncertainty float64 `json:"relativeUncertainty"`
+	Fastest             bool    `json:"fastest"`
+}
+
+// ScanDocument is the JSON document of a parameter scan, as saved by
+// SaveScanJSON. Durations are in seconds.
+type ScanDocument struct {
+	SchemaVersion int                 `json:"schemaVersion"`
+	Command       string              `json:"command"` // Command template
+	Parameter     string              `json:"parameter"`
+	Values        []ScanValueDocument `json:"values"`
+	Fastest       string              `json:"fastest,omitempty"` // Omitted when no value succeeded
+}
+
+// ScanValueDocument is the result of one value of a parameter scan
+type ScanValueDocument struct {
+	Value string `json:"value"`
+	ComparedCommandDocument
+}
+
+// DiffDocument is the JSON document of the comparison of a candidate benchmark
+// against a baseline, as saved by SaveDiffJSON
+type DiffDocument struct {
+	SchemaVersion int `json:"schemaVersion"`
+	DiffResultDocument
+}
+
+// DiffResultDocument is a comparison of two samples, also used for each
+// configuration of a matrix comparison. Durations are in seconds.
+type DiffResultDocument struct {
+	Baseline           SampleDocument       `json:"baseline"`
+	Candidate          SampleDocument       `json:"candidate"`
+	Confidence         float64              `json:"confidence"`
+	Difference         float64              `json:"difference"` // Candidate mean - baseline mean
+	RelativeDifference float64              `json:"relativeDifference"`
+	DifferenceCI       ConfidenceInterval   `json:"differenceCI"`
+	EffectSize         float64              `json:"effectSize"` // Cohen's d
+	EffectMagnitude    string               `json:"effectMagnitude"`
+	Welch              WelchDocument        `json:"welch"`
+	MannWhitney        *MannWhitneyDocument `json:"mannWhitney,omitempty"` // Only with per-run durations
+	Significant        bool                 `json:"significant"`
+	Verdict            Verdict              `json:"verdict"`
+	Summary            string               `json:"summary"`
+}
+
+// SampleDocument summarizes one side of a comparison
+type SampleDocument struct {
+	Name   string  `json:"name"`
+	N      int     `json:"n"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stdDev"`
+}
+
+// WelchDocument is the result of Welch's t-test on the means
+type WelchDocument struct {
+	T  float64 `json:"t"`
+	DF float64 `json:"df"`
+	P  float64 `json:"p"`
+}
+
+// MannWhitneyDocument is the result of the Mann–Whitney U test
+type MannWhitneyDocument struct {
+	U float64 `json:"u"`
+	P float64 `json:"p"`
+}
+
+// NewComparisonDocument converts a comparison of several commands to its JSON document
+func NewComparisonDocument(comparison *Comparison) ComparisonDocument {
+	doc := ComparisonDocument{
+		SchemaVersion: SchemaVersion,
+		Commands:      make([]ComparedCommandDocument, 0, len(comparison.Results)),
+		Summary:       comparisonSummary(comparison),
+	}
+	for i, r := range comparison.Results {
+		doc.Commands = append(doc.Commands, newComparedCommandDocument(r, r.Config.Label(), comparison.Relative[i]))
+	}
+	if comparison.Fastest >= 0 {
+		doc.Fastest = comparison.Results[comparison.Fastest].Config.Label()
+	}
+	return doc
+}
+
+// NewScanDocument converts a parameter scan to its JSON document
+func NewScanDocument(scan *ScanResult) ScanDocument {
+	comparison := Compare(scan.Results)
+	doc := ScanDocument{
+		SchemaVersion: SchemaVersion,
+		Command:       scan.Command,
+		Parameter:     scan.Parameter,
+		Values:        make([]ScanValueDocument, 0, len(scan.Results)),
+	}
+	for i, r := range scan.Results {
+		doc.Values = append(doc.Values, ScanValueDocument{
+			Value:                   r.Config.Parameter.Value,
+			ComparedCommandDocument: newComparedCommandDocument(r, r.Config.Name, comparison.Relative[i]),
+		})
+	}
+	if comparison.Fastest >= 0 {
+		doc.Fastest = scan.Results[comparison.Fastest].Config.Parameter.Value
+	}
+	return doc
+}
+
+// newComparedCommandDocument converts the statistics of a result and its relative speed
+func newComparedCommandDocument(r *Result, name string, relative RelativeSpeed) ComparedCommandDocument {
+	return ComparedCommandDocument{
+		Name:                name,
+		Command:             r.Config.Command,
+		SuccessRate:         r.SuccessRate,
+		N:                   r.Stats.N,
+		Mean:                r.Stats.Mean,
+		Median:              r.Stats.Median,
+		StdDev:              r.Stats.StdDev,
+		Min:                 r.Stats.Min,
+		Max:                 r.Stats.Max,
+		Relative:            relative.Ratio,
+		RelativeUncertainty: relative.Uncertainty,
+		Fastest:             relative.Fastest,
+	}
+}
+
+// NewDiffDocument converts a comparison of two benchmarks to its JSON document
+func NewDiffDocument(diff Diff) DiffDocument {
+	return DiffDocument{SchemaVersion: SchemaVersion, DiffResultDocument: NewDiffResultDocument(diff)}
+}
+
+// NewDiffResultDocument converts a comparison of two samples to the JSON
+// representation shared by single and matrix comparisons
+func NewDiffResultDocument(diff Diff) DiffResultDocument {
+	sample := func(s SampleSummary) SampleDocument {
+		return SampleDocument{Name: s.Name, N: s.N, Mean: s.Mean, Median: s.Median, StdDev: s.StdDev}
+	}
+
+	doc := DiffResultDocument{
+		Baseline:           sample(diff.Baseline),
+		Candidate:          sample(diff.Candidate),
+		Confidence:         diff.Confidence,
+		Difference:         diff.Difference,
+		RelativeDifference: diff.RelativeDifference,
+		DifferenceCI:       diff.DifferenceCI,
+		EffectSize:         diff.EffectSize,
+		EffectMagnitude:    diff.EffectMagnitude(),
+		Welch:              WelchDocument{T: diff.WelchT, DF: diff.WelchDF, P: diff.WelchP},
+		Significant:        diff.Significant(),
+		Verdict:            diff.Verdict,
+		Summary:            diff.Summary(),
+	}
+	if diff.MannWhitney {
+		doc.MannWhitney = &MannWhitneyDocument{U: diff.MannWhitneyU, P: diff.MannWhitneyP}
+	}
+	return doc
+}
+
+// LoadResultDocument reads a benchmark JSON result saved by SaveJSON and
+// migrates it to the current SchemaVersion
+func LoadResultDocument(path string) (*ResultDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResultDocument(data)
+}
+
+// ParseResultDocument parses a benchmark JSON result and migrates it to the
+// current SchemaVersion
+func ParseResultDocument(data []byte) (*ResultDocument, error) {
+	var doc ResultDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("not a caliper result: %w", err)
+	}
+	if doc.Runs == nil {
+		return nil, fmt.Errorf("not a caliper result: no runs")
+	}
+	if err := CheckSchemaVersion(doc.SchemaVersion); err != nil {
+		return nil, err
+	}
+
+	if doc.SchemaVersion == 0 {
+		if err := migrateResultDocumentV0(&doc, data); err != nil {
+			return nil, err
+		}
+	}
+	return &doc, nil
+}
+
+// LoadResult reads a benchmark JSON result saved by SaveJSON, of any schema version
+func LoadResult(path string) (*Result, error) {
+	doc, err := LoadResultDocument(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc.Result(), nil
+}
+
+// CheckSchemaVersion returns an error for a schema version this caliper cannot read
+func CheckSchemaVersion(version int) error {
+	if version < 0 || version > SchemaVersion {
+		return fmt.Errorf("schema version %d is not supported (this caliper reads versions 0 to %d); upgrade caliper", version, SchemaVersion)
+	}
+	return nil
+}
+
+// migrateResultDocumentV0 converts an unversioned document. Its single warm-up
+// run becomes the first of WarmupRuns, and the statistics are recomputed from
+// the runs, since version 0 lacked most of them.
+func migrateResultDocumentV0(doc *ResultDocument, data []byte) error {
+	var v0 struct {
+		WarmupRun *struct {
+			Duration float64 `json:"duration"`
+			Success  bool    `json:"success"`
+			Error    string  `json:"error"`
+		} `json:"warmupRun"`
+	}
+	if err := json.Unmarshal(data, &v0); err != nil {
+		return fmt.Errorf("not a caliper result: %w", err)
+	}
+	if v0.WarmupRun != nil && len(doc.WarmupRuns) == 0 {
+		status := RunStatusSuccess
+		if !v0.WarmupRun.Success {
+			status = RunStatusFailed
+		}
+		doc.WarmupRuns = []WarmupRunDocument{{
+			WarmupNumber: 1,
+			Session:      1,
+			Attempt:      1,
+			Duration:     v0.WarmupRun.Duration,
+			Success:      v0.WarmupRun.Success,
+			Status:       status,
+			Error:        v0.WarmupRun.Error,
+		}}
+	}
+
+	for i := range doc.Runs {
+		run := &doc.Runs[i]
+		if run.Status == "" {
+			run.Status = RunStatusFailed
+			if run.Success {
+				run.Status = RunStatusSuccess
+			}
+		}
+	}
+
+	result := doc.Result()
+	if result.StopReason == "" {
+		result.StopReason = StopReasonRunCount
+	}
+	result.calculateStatistics()
+	*doc = NewResultDocument(result)
+	return nil
+}
+
+// seconds converts seconds to a time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// envEntries converts an environment map to sorted KEY=VALUE entries
+func envEntries(env map[string]string) []string {
+	entries := make([]string, 0, len(env))
+	for key, value := range env {
+		entries = append(entries, key+"="+value)
+	}
+	sort.Strings(entries)
+	return entries
+}
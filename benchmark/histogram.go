@@ -0,0 +1,200 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Histogram limits. The bin count is chosen like numpy's "auto": the larger of
+// Sturges' rule, which suits few runs, and the Freedman–Diaconis rule, which
+// suits many, capped so the chart stays readable. Below minFreedmanDiaconisRuns
+// only Sturges' rule is used: with a narrow IQR and one slow run, the
+// Freedman–Diaconis rule would spread a handful of runs over mostly empty bins.
+const (
+	maxHistogramBins        = 20
+	minFreedmanDiaconisRuns = 20
+	minModalRuns            = 10 // Fewer runs are too sparse to tell peaks from noise
+)
+
+// sparkLevels are the block characters of a sparkline, from empty to full
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Histogram counts durations in bins of equal width
+type Histogram struct {
+	Min        float64 // Lower edge of the first bin in seconds
+	Width      float64 // Width of each bin in seconds (0 when all durations are equal)
+	Counts     []int   // Number of durations in each bin
+	N          int     // Total number of durations
+	Bimodality float64 // Sarle's bimodality coefficient of the durations (0 with fewer than 4)
+}
+
+// NewHistogram bins durations automatically (see maxHistogramBins)
+func NewHistogram(durations []float64) Histogram {
+	n := len(durations)
+	if n == 0 {
+		return Histogram{}
+	}
+
+	sorted := make([]float64, n)
+	copy(sorted, durations)
+	sort.Float64s(sorted)
+	low, high := sorted[0], sorted[n-1]
+	if high == low {
+		return Histogram{Min: low, Counts: []int{n}, N: n}
+	}
+
+	bins := int(math.Ceil(math.Log2(float64(n)))) + 1 // Sturges
+	if iqr := percentile(sorted, 75) - percentile(sorted, 25); iqr > 0 && n >= minFreedmanDiaconisRuns {
+		width := 2 * iqr / math.Cbrt(float64(n)) // Freedman–Diaconis
+		bins = max(bins, int(math.Ceil((high-low)/width)))
+	}
+	bins = min(bins, maxHistogramBins)
+
+	h := Histogram{Min: low, Width: (high - low) / float64(bins), Counts: make([]int, bins), N: n, Bimodality: bimodalityCoefficient(sorted)}
+	for _, d := range sorted {
+		// The largest duration closes the last bin
+		h.Counts[min(int((d-low)/h.Width), bins-1)]++
+	}
+	return h
+}
+
+// Edges returns the lower and upper edge of bin i in seconds
+func (h Histogram) Edges(i int) (low, high float64) {
+	return h.Min + float64(i)*h.Width, h.Min + float64(i+1)*h.Width
+}
+
+// Modes counts the peaks of the histogram. A new peak needs at least 10% of
+// the runs (and 3 runs), and must be separated from the previous peak by a
+// valley at most half as high as either of them.
+func (h Histogram) Modes() int {
+	if h.N < minModalRuns {
+		return 1
+	}
+
+	minPeak := max(3, int(math.Ceil(0.1*float64(h.N))))
+	modes, peak, valley := 0, 0, 0
+	for _, count := range h.Counts {
+		switch {
+		case modes == 0:
+			if count >= minPeak {
+				modes, peak, valley = 1, count, count
+			}
+		case count >= minPeak && 2*valley <= peak && 2*valley <= count:
+			modes, peak, valley = modes+1, count, count
+		case count > peak:
+			peak, valley = count, count
+		case count < valley:
+			valley = count
+		}
+	}
+	return max(modes, 1)
+}
+
+// MultiModal reports whether the durations fall into separate groups, e.g.
+// runs with a warm cache and runs without. The histogram must show several
+// peaks and the bimodality coefficient must exceed 5/9, its value for a
+// uniform distribution: with few runs, random gaps between bins alone often
+// look like separate peaks.
+func (h Histogram) MultiModal() bool {
+	return h.Bimodality > 5.0/9 && h.Modes() > 1
+}
+
+// String renders the histogram as ASCII bars, one row per bin with its
+// duration range and count
+func (h Histogram) String() string {
+	if h.N == 0 {
+		return ""
+	}
+
+	labels := make([]string, len(h.Counts))
+	labelWidth := 0
+	maxCount := 0
+	for i, count := range h.Counts {
+		labels[i] = h.binLabel(i)
+		labelWidth = max(labelWidth, len([]rune(labels[i])))
+		maxCount = max(maxCount, count)
+	}
+
+	var sb strings.Builder
+	for i, count := range h.Counts {
+		padding := strings.Repeat(" ", labelWidth-len([]rune(labels[i])))
+		sb.WriteString(fmt.Sprintf("%s%s │%s %d\n", padding, labels[i], Bar(float64(count), float64(maxCount), chartWidth), count))
+	}
+	return sb.String()
+}
+
+// Sparkline renders the histogram as a single line of block characters, e.g. "▁▃█▅▁▁▄▂"
+func (h Histogram) Sparkline() string {
+	maxCount := 0
+	for _, count := range h.Counts {
+		maxCount = max(maxCount, count)
+	}
+
+	var sb strings.Builder
+	for _, count := range h.Counts {
+		level := 0
+		if maxCount > 0 {
+			level = count * (len(sparkLevels) - 1) / maxCount
+		}
+		if count > 0 {
+			level = max(level, 1)
+		}
+		sb.WriteRune(sparkLevels[level])
+	}
+	return sb.String()
+}
+
+// binLabel formats the duration range of bin i, e.g. "1.02s – 1.04s", rounded
+// to a tenth of the bin width so that neighbouring bins stay distinguishable
+func (h Histogram) binLabel(i int) string {
+	if h.Width == 0 {
+		return formatShortDuration(h.Min)
+	}
+
+	unit := time.Nanosecond
+	for unit*10 <= time.Duration(h.Width*float64(time.Second)/10) {
+		unit *= 10
+	}
+	low, high := h.Edges(i)
+	round := func(seconds float64) string {
+		return time.Duration(seconds * float64(time.Second)).Round(unit).String()
+	}
+	return round(low) + " – " + round(high)
+}
+
+// bimodalityCoefficient returns Sarle's bimodality coefficient
+// (g² + 1) / (k + 3(n-1)² / ((n-2)(n-3))) from the sample skewness g and the
+// sample excess kurtosis k, both with small-sample corrections. It is about
+// 1/3 for a normal distribution, 5/9 for a uniform one, and close to 1 for two
+// separate groups.
+func bimodalityCoefficient(durations []float64) float64 {
+	n := float64(len(durations))
+	if n < 4 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, d := range durations {
+		mean += d
+	}
+	mean /= n
+
+	var m2, m3, m4 float64
+	for _, d := range durations {
+		dev := d - mean
+		m2 += dev * dev
+		m3 += dev * dev * dev
+		m4 += dev * dev * dev * dev
+	}
+	m2, m3, m4 = m2/n, m3/n, m4/n
+	if m2 == 0 {
+		return 0
+	}
+
+	skewness := math.Sqrt(n*(n-1)) / (n - 2) * m3 / math.Pow(m2, 1.5)
+	kurtosis := (n - 1) / ((n - 2) * (n - 3)) * ((n+1)*(m4/(m2*m2)-3) + 6)
+	return (skewness*skewness + 1) / (kurtosis + 3*(n-1)*(n-1)/((n-2)*(n-3)))
+}
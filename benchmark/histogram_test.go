@@ -0,0 +1,39 @@
+package benchmark
+
+import "testing"
+
+func TestNewHistogramBins(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []float64
+		bins      int
+	}{
+		// A narrow IQR and one slow run: Freedman–Diaconis alone would ask for 20 bins
+		{"small sample", []float64{1.00, 1.01, 1.02, 1.03, 1.04, 2.00}, 4},
+		{"equal durations", []float64{1, 1, 1}, 1},
+		{"two runs", []float64{1, 2}, 2},
+	}
+	for _, tt := range tests {
+		h := NewHistogram(tt.durations)
+		if len(h.Counts) != tt.bins {
+			t.Errorf("%s: %d bins, want %d", tt.name, len(h.Counts), tt.bins)
+		}
+		total := 0
+		for _, count := range h.Counts {
+			total += count
+		}
+		if total != len(tt.durations) {
+			t.Errorf("%s: bins hold %d durations, want %d", tt.name, total, len(tt.durations))
+		}
+	}
+
+	// With many runs the Freedman–Diaconis rule still applies, up to the cap
+	many := make([]float64, 200)
+	for i := range many {
+		many[i] = 1 + float64(i%100)/100
+	}
+	many[199] = 10
+	if h := NewHistogram(many); len(h.Counts) != maxHistogramBins {
+		t.Errorf("200 runs: %d bins, want %d", len(h.Counts), maxHistogramBins)
+	}
+}
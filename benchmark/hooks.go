@@ -0,0 +1,91 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Hook names used in HookFailure records
+const (
+	HookSetup    = "setup"    // Runs once before all runs
+	HookPrepare  = "prepare"  // Runs before every run, including warm-up (untimed)
+	HookConclude = "conclude" // Runs after every run, including warm-up (untimed)
+	HookCleanup  = "cleanup"  // Runs once after all runs
+	HookService  = "service"  // Runs in the background during all runs
+)
+
+// HookFailure records a failed hook execution
+type HookFailure struct {
+	Hook      string // One of HookSetup, HookPrepare, HookConclude, HookCleanup, HookService
+	RunNumber int    // Run the hook belonged to (0 for warm-up, setup and cleanup)
+	Error     string
+}
+
+// runHook executes a hook command outside of any measurement.
+// An empty command is a no-op. The per-run timeout applies to hooks as well.
+func runHook(ctx context.Context, command string, config Config) error {
+	if command == "" {
+		return nil
+	}
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	cmd, err := newShellCommand(ctx, config.hookShell(), command, config.Debug)
+	if err != nil {
+		return err
+	}
+	config.applyEnvironment(cmd.Cmd)
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", config.Timeout)
+	}
+	return err
+}
+
+// executeRun runs the prepare hook, the timed command and the conclude hook for one run.
+// A failing prepare hook skips the run; a failing conclude hook keeps the measurement.
+// logName identifies the run's log files (e.g. "run-3" or "warmup-1").
+// Hook failures caused by an interruption are not recorded.
+func executeRun(ctx context.Context, runNumber int, logName string, config Config) (RunResult, []HookFailure) {
+	var failures []HookFailure
+
+	if err := runHook(ctx, config.Prepare, config); err != nil {
+		if ctx.Err() != nil {
+			return RunResult{
+				RunNumber: runNumber,
+				Status:    RunStatusInterrupted,
+				Error:     "interrupted",
+				ExitCode:  -1,
+			}, nil
+		}
+		failures = append(failures, HookFailure{Hook: HookPrepare, RunNumber: runNumber, Error: err.Error()})
+		return RunResult{
+			RunNumber: runNumber,
+			Success:   false,
+			Status:    RunStatusHookFailed,
+			Error:     fmt.Sprintf("prepare hook failed: %v", err),
+			ExitCode:  -1,
+		}, failures
+	}
+
+	var result RunResult
+	if config.MeasureStartup {
+		result = executeStartup(ctx, runNumber, logName, config)
+	} else {
+		result = executeCommand(ctx, runNumber, logName, config)
+	}
+	if ctx.Err() != nil {
+		return result, failures
+	}
+
+	if err := runHook(ctx, config.Conclude, config); err != nil {
+		failures = append(failures, HookFailure{Hook: HookConclude, RunNumber: runNumber, Error: err.Error()})
+	}
+
+	return result, failures
+}
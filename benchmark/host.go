@@ -0,0 +1,375 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Host noise thresholds. A sample is noisy when any of them is exceeded.
+const (
+	maxBackgroundCPU = 0.1  // Share of the logical CPUs used by processes outside the benchmark
+	maxSteal         = 0.05 // Share of CPU time stolen by the hypervisor
+	maxIOWait        = 0.1  // Share of CPU time waiting for I/O, only checked between runs
+	busyProcessCPU   = 0.1  // CPUs a process must use to be listed as busy
+	maxBusyProcesses = 5    // Busy processes kept per sample, busiest first
+)
+
+// Quiet host checks. Samples taken while no run is in progress last
+// quietWindow; with Config.RequireQuietHost, a run waits up to
+// quietHostTimeout for a quiet sample before the benchmark is aborted.
+const (
+	quietWindow      = time.Second
+	quietHostTimeout = 5 * time.Minute
+	clockTicks       = 100 // USER_HZ, the unit of the CPU times in /proc
+)
+
+// HostSample describes the background load on the host over a period of time.
+// Samples are read from /proc and are only available on Linux.
+type HostSample struct {
+	Run           int           `json:"run"`                     // Measured run the sample covers (0 when taken while no run was in progress)
+	Time          time.Time     `json:"time"`                    // End of the sampled period
+	Window        float64       `json:"window"`                  // Length of the sampled period in seconds
+	CPUs          int           `json:"cpus"`                    // Logical CPUs of the host
+	Load1         float64       `json:"load1"`                   // 1-minute load average at the end of the period
+	BackgroundCPU float64       `json:"backgroundCpu"`           // CPUs used by processes outside the benchmark
+	Steal         float64       `json:"steal"`                   // Share of CPU time stolen by the hypervisor
+	IOWait        float64       `json:"iowait"`                  // Share of CPU time waiting for I/O
+	BusyProcesses []BusyProcess `json:"busyProcesses,omitempty"` // Processes outside the benchmark using at least 0.1 CPUs
+	Reasons       []string      `json:"reasons,omitempty"`       // Why the sample is noisy (empty when quiet)
+}
+
+// BusyProcess is a process outside the benchmark that used the CPU during a sample
+type BusyProcess struct {
+	PID  int     `json:"pid"`
+	Name string  `json:"name"`
+	CPU  float64 `json:"cpu"` // CPUs used on average over the sample
+}
+
+// Noisy reports whether background load exceeded a threshold during the sample
+func (s HostSample) Noisy() bool {
+	return len(s.Reasons) > 0
+}
+
+// Description describes why the sample is noisy and the busiest processes,
+// e.g. "other processes used 2.1 of 8 CPUs (cc1plus 1.2, chrome 0.6)"
+func (s HostSample) Description() string {
+	description := strings.Join(s.Reasons, ", ")
+	if len(s.BusyProcesses) > 0 {
+		busy := make([]string, len(s.BusyProcesses))
+		for i, p := range s.BusyProcesses {
+			busy[i] = fmt.Sprintf("%s %.1f", p.Name, p.CPU)
+		}
+		description += " (" + strings.Join(busy, ", ") + ")"
+	}
+	return description
+}
+
+// NoisyHostSamples returns the host samples that exceeded a noise threshold
+func (r *Result) NoisyHostSamples() []HostSample {
+	var noisy []HostSample
+	for _, s := range r.HostSamples {
+		if s.Noisy() {
+			noisy = append(noisy, s)
+		}
+	}
+	return noisy
+}
+
+// hostSnapshot is a reading of the host's CPU counters and processes
+type hostSnapshot struct {
+	time      time.Time
+	total     uint64 // CPU time of all CPUs in clock ticks
+	busy      uint64 // CPU time not spent idle, waiting for I/O or stolen
+	steal     uint64
+	iowait    uint64
+	reaped    float64 // CPU time of caliper and the children it waited for, in clock ticks
+	processes map[int]processTimes
+}
+
+// processTimes is the parent, process group and CPU time of a process
+type processTimes struct {
+	name       string
+	ppid       int
+	pgrp       int
+	started    uint64 // Start time in clock ticks since boot, which tells reused PIDs apart
+	ticks      uint64 // User and system time in clock ticks
+	childTicks uint64 // User and system time of the children it waited for
+}
+
+// hostMonitor samples the background load between runs. Each sample covers
+// the period since the previous one.
+type hostMonitor struct {
+	last   hostSnapshot
+	groups map[int]bool // Process groups of caliper's work besides its descendants
+}
+
+// newHostMonitor returns a monitor, or nil when /proc cannot be read (e.g. on macOS)
+func newHostMonitor() *hostMonitor {
+	snapshot, err := readHostSnapshot()
+	if err != nil {
+		return nil
+	}
+	return &hostMonitor{last: snapshot, groups: make(map[int]bool)}
+}
+
+// excludeGroup counts a process group as caliper's own work, so that e.g. a
+// service that daemonizes out of caliper's process tree is not background load
+func (m *hostMonitor) excludeGroup(pgid int) {
+	m.groups[pgid] = true
+}
+
+// sample returns the background load since the previous sample; run is the
+// measured run that was in progress during that period (0 for none)
+func (m *hostMonitor) sample(run int) HostSample {
+	snapshot, err := readHostSnapshot()
+	if err != nil {
+		return HostSample{Run: run, Time: time.Now(), CPUs: runtime.NumCPU()}
+	}
+	sample := compareSnapshots(m.last, snapshot, run, m.groups)
+	m.last = snapshot
+	return sample
+}
+
+// sampleIdle waits for quietWindow while no run is in progress and returns
+// the background load during that window
+func (m *hostMonitor) sampleIdle(ctx context.Context) HostSample {
+	if snapshot, err := readHostSnapshot(); err == nil {
+		m.last = snapshot
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(quietWindow):
+	}
+	return m.sample(0)
+}
+
+// waitForQuiet samples the host until a sample is quiet, for up to
+// quietHostTimeout. It returns the quiet sample, or an error describing the
+// last noisy one.
+func (m *hostMonitor) waitForQuiet(ctx context.Context, noisy HostSample, reporter Reporter) (HostSample, error) {
+	reporter.WaitingForQuietHost(noisy)
+	deadline := time.Now().Add(quietHostTimeout)
+	for {
+		sample := m.sampleIdle(ctx)
+		if !sample.Noisy() || ctx.Err() != nil {
+			return sample, nil
+		}
+		if time.Now().After(deadline) {
+			return sample, fmt.Errorf("host did not quiet down within %s: %s", quietHostTimeout, sample.Description())
+		}
+	}
+}
+
+// compareSnapshots computes the background load between two snapshots: the
+// busy CPU time of the host minus caliper's own work. Its own work is the CPU
+// time of caliper, of the processes it started and waited for (the command,
+// hooks and service), of the live processes descending from it and of the
+// excluded process groups. Work that caliper's processes hand to a daemon
+// started elsewhere, e.g. dockerd for docker build, counts as background load.
+//
+// Deriving the load from /proc/stat counts processes that started and exited
+// between the snapshots, such as the compilers of a competing parallel build.
+// Processes are only read to name the busiest ones.
+func compareSnapshots(start, end hostSnapshot, run int, groups map[int]bool) HostSample {
+	window := end.time.Sub(start.time).Seconds()
+	sample := HostSample{Run: run, Time: end.time, Window: window, CPUs: runtime.NumCPU()}
+	if load, err := readLoadAverage(); err == nil {
+		sample.Load1 = load
+	}
+
+	if total := end.total - start.total; end.total > start.total {
+		sample.Steal = float64(end.steal-start.steal) / float64(total)
+		sample.IOWait = float64(end.iowait-start.iowait) / float64(total)
+	}
+
+	if window > 0 {
+		self := os.Getpid()
+		own := func(pid int, snapshot hostSnapshot) bool {
+			return groups[snapshot.processes[pid].pgrp] || descendsFrom(pid, self, snapshot.processes)
+		}
+
+		// Children reaped during the window add their whole CPU time to
+		// reaped; the part before the window was already caliper's work
+		ownTicks := end.reaped - start.reaped
+		for pid, before := range start.processes {
+			if p, ok := end.processes[pid]; (!ok || restarted(before, p)) && own(pid, start) {
+				ownTicks -= float64(before.ticks + before.childTicks)
+			}
+		}
+
+		for pid, p := range end.processes {
+			if pid == self {
+				continue // Counted in reaped
+			}
+			ticks, childTicks := p.ticks, p.childTicks
+			if before, ok := start.processes[pid]; ok && !restarted(before, p) {
+				ticks -= before.ticks
+				childTicks -= before.childTicks
+			}
+			if own(pid, end) {
+				ownTicks += float64(ticks + childTicks)
+				continue
+			}
+			cpu := float64(ticks) / clockTicks / window
+			if cpu >= busyProcessCPU {
+				sample.BusyProcesses = append(sample.BusyProcesses, BusyProcess{PID: pid, Name: p.name, CPU: cpu})
+			}
+		}
+		sort.Slice(sample.BusyProcesses, func(i, j int) bool {
+			return sample.BusyProcesses[i].CPU > sample.BusyProcesses[j].CPU
+		})
+		if len(sample.BusyProcesses) > maxBusyProcesses {
+			sample.BusyProcesses = sample.BusyProcesses[:maxBusyProcesses]
+		}
+
+		if end.busy > start.busy {
+			background := float64(end.busy-start.busy) - ownTicks
+			sample.BackgroundCPU = math.Max(background, 0) / clockTicks / window
+		}
+	}
+
+	if sample.BackgroundCPU > maxBackgroundCPU*float64(sample.CPUs) {
+		sample.Reasons = append(sample.Reasons, fmt.Sprintf("other processes used %.1f of %d CPUs", sample.BackgroundCPU, sample.CPUs))
+	}
+	if sample.Steal > maxSteal {
+		sample.Reasons = append(sample.Reasons, fmt.Sprintf("the hypervisor stole %.0f%% of CPU time", sample.Steal*100))
+	}
+	// While a run is in progress, the command itself may wait for I/O
+	if run == 0 && sample.IOWait > maxIOWait {
+		sample.Reasons = append(sample.Reasons, fmt.Sprintf("CPUs waited for I/O %.0f%% of the time", sample.IOWait*100))
+	}
+	return sample
+}
+
+// restarted reports whether a PID was reused by another process between two snapshots
+func restarted(before, after processTimes) bool {
+	return before.started != after.started || after.ticks < before.ticks || after.childTicks < before.childTicks
+}
+
+// descendsFrom reports whether pid is ancestor or one of its descendants
+func descendsFrom(pid, ancestor int, processes map[int]processTimes) bool {
+	for depth := 0; pid > 1 && depth < 64; depth++ {
+		if pid == ancestor {
+			return true
+		}
+		p, ok := processes[pid]
+		if !ok {
+			return false
+		}
+		pid = p.ppid
+	}
+	return false
+}
+
+// readHostSnapshot reads the CPU counters from /proc/stat and the CPU time
+// of every process from /proc/<pid>/stat
+func readHostSnapshot() (hostSnapshot, error) {
+	snapshot := hostSnapshot{time: time.Now(), processes: make(map[int]processTimes)}
+
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return snapshot, err
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 9 || fields[0] != "cpu" {
+		return snapshot, fmt.Errorf("unexpected /proc/stat format")
+	}
+	// user nice system idle iowait irq softirq steal (guest time is part of user)
+	for i, field := range fields[1:9] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return snapshot, fmt.Errorf("unexpected /proc/stat format: %w", err)
+		}
+		snapshot.total += value
+		switch i {
+		case 3: // idle
+		case 4:
+			snapshot.iowait = value
+		case 7:
+			snapshot.steal = value
+		default:
+			snapshot.busy += value
+		}
+	}
+
+	var self, children syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &self); err != nil {
+		return snapshot, err
+	}
+	if err := syscall.Getrusage(syscall.RUSAGE_CHILDREN, &children); err != nil {
+		return snapshot, err
+	}
+	for _, t := range []syscall.Timeval{self.Utime, self.Stime, children.Utime, children.Stime} {
+		snapshot.reaped += time.Duration(t.Nano()).Seconds() * clockTicks
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return snapshot, err
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// Processes may exit while being read
+		if p, err := readProcessTimes(pid); err == nil {
+			snapshot.processes[pid] = p
+		}
+	}
+	return snapshot, nil
+}
+
+// readProcessTimes parses /proc/<pid>/stat: "pid (comm) state ppid pgrp ... utime stime cutime cstime ... starttime ..."
+func readProcessTimes(pid int) (processTimes, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return processTimes{}, err
+	}
+	stat := string(data)
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return processTimes{}, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return processTimes{}, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	pgrp, _ := strconv.Atoi(fields[2])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	cutime, _ := strconv.ParseUint(fields[13], 10, 64)
+	cstime, _ := strconv.ParseUint(fields[14], 10, 64)
+	started, _ := strconv.ParseUint(fields[19], 10, 64)
+	return processTimes{
+		name:       stat[open+1 : end],
+		ppid:       ppid,
+		pgrp:       pgrp,
+		started:    started,
+		ticks:      utime + stime,
+		childTicks: cutime + cstime,
+	}, nil
+}
+
+// readLoadAverage returns the 1-minute load average from /proc/loadavg
+func readLoadAverage() (float64, error) {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected /proc/loadavg format")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
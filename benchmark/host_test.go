@@ -0,0 +1,53 @@
+package benchmark
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCompareSnapshots(t *testing.T) {
+	self := os.Getpid()
+	start := hostSnapshot{
+		time:   time.Unix(1000, 0),
+		total:  1000,
+		busy:   400,
+		reaped: 20,
+		processes: map[int]processTimes{
+			self: {name: "caliper", ppid: 1, pgrp: self, started: 1, ticks: 10},
+			200:  {name: "make", ppid: 1, pgrp: 200, started: 5, ticks: 40},
+			300:  {name: "bash", ppid: self, pgrp: 300, started: 7, ticks: 5}, // The run, reaped during the window
+			400:  {name: "cc1plus", ppid: 200, pgrp: 200, started: 8, ticks: 10},
+		},
+	}
+	end := hostSnapshot{
+		time:   time.Unix(1002, 0),
+		total:  1400,
+		busy:   800, // 400 ticks busy over 2 s
+		reaped: 122, // caliper took 2 ticks and the run 100, 5 of them before the window
+		processes: map[int]processTimes{
+			self: {name: "caliper", ppid: 1, pgrp: self, started: 1, ticks: 12},
+			200:  {name: "make", ppid: 1, pgrp: 200, started: 5, ticks: 50},
+			400:  {name: "cc1plus", ppid: 200, pgrp: 200, started: 9, ticks: 90}, // PID reused by a new compiler
+			500:  {name: "postgres", ppid: 1, pgrp: 500, started: 20, ticks: 60}, // Daemonized service
+		},
+	}
+
+	// Own work: 102 reaped - 5 of the run before the window + 60 of the service group
+	sample := compareSnapshots(start, end, 1, map[int]bool{500: true})
+	own := 102.0 - 5 + 60
+	if want := (400 - own) / clockTicks / 2; math.Abs(sample.BackgroundCPU-want) > 1e-9 {
+		t.Errorf("background CPU = %g, want %g", sample.BackgroundCPU, want)
+	}
+
+	// The new compiler counts with its full time, the service not at all
+	if len(sample.BusyProcesses) != 1 || sample.BusyProcesses[0].Name != "cc1plus" || sample.BusyProcesses[0].CPU != 0.45 {
+		t.Errorf("busy processes = %+v, want cc1plus at 0.45 CPUs", sample.BusyProcesses)
+	}
+
+	// Without the exclusion, the service is background load
+	if sample := compareSnapshots(start, end, 1, nil); sample.BackgroundCPU <= 0.5 || len(sample.BusyProcesses) != 2 {
+		t.Errorf("without the service group: background CPU = %g, busy = %+v", sample.BackgroundCPU, sample.BusyProcesses)
+	}
+}
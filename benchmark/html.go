@@ -0,0 +1,415 @@
+package benchmark
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// HTMLReport is a self-contained HTML page made of sections. It renders the
+// HTML reports of single benchmarks and, in the matrix package, of matrix
+// benchmarks.
+type HTMLReport struct {
+	Title     string
+	Generated string
+	Warnings  []string
+	Sections  []HTMLSection
+}
+
+// HTMLSection is a titled part of an HTML report. Its parts are shown in
+// field order and left out when empty.
+type HTMLSection struct {
+	Title    string
+	Text     string      // Paragraph below the title
+	Warnings []string    // Warnings about the section
+	Fields   []HTMLField // Label/value list
+	Bars     []HTMLBar   // Horizontal bar chart
+	Table    *HTMLTable
+	Pre      string // Preformatted text, e.g. a histogram
+}
+
+// HTMLField is a labelled value; Code values are shown in a monospace font
+type HTMLField struct {
+	Label string
+	Value string
+	Code  bool
+}
+
+// HTMLTable is a table with a header row
+type HTMLTable struct {
+	Header []string
+	Rows   [][]string
+}
+
+// HTMLBar is a bar of a bar chart
+type HTMLBar struct {
+	Label   string
+	Value   string  // Text shown next to the bar
+	Percent float64 // Length relative to the longest possible bar, 0-100
+	Failed  bool    // Drawn in the failure colour
+}
+
+// NewHTMLBars scales values to bars, the largest value filling the chart
+func NewHTMLBars(labels []string, values []float64, failed []bool) []HTMLBar {
+	var max float64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	bars := make([]HTMLBar, len(values))
+	for i, v := range values {
+		bars[i] = HTMLBar{Label: labels[i], Value: formatShortDuration(v), Failed: failed[i]}
+		if max > 0 {
+			bars[i].Percent = v / max * 100
+		}
+	}
+	return bars
+}
+
+// Write renders the report as an HTML page to w
+func (r HTMLReport) Write(w io.Writer) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 1100px; margin: 2em auto; padding: 0 1em; line-height: 1.5; }
+h1 { border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .2em; margin-top: 1.8em; }
+.generated { color: #656d76; }
+.warning { background: #fff8c5; border-left: 4px solid #d4a72c; padding: .5em 1em; margin: .5em 0; }
+dl { display: grid; grid-template-columns: max-content auto; gap: .2em 1.5em; }
+dt { font-weight: 600; }
+dd { margin: 0; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 90%; }
+pre { background: #f6f8fa; padding: 1em; overflow-x: auto; }
+table { border-collapse: collapse; margin: .5em 0; }
+th, td { border: 1px solid #d0d7de; padding: .3em .8em; text-align: left; white-space: pre-line; }
+th { background: #f6f8fa; }
+.bars { margin: .5em 0; display: grid; grid-template-columns: max-content auto; gap: .2em .8em; align-items: center; }
+.bar { display: flex; align-items: center; gap: .5em; }
+.bar span { display: inline-block; height: 1em; background: #54aeff; }
+.bar.failed span { background: #ff8182; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .Generated}}
+<p class="generated">Generated: {{.Generated}}</p>
+{{- end}}
+{{- range .Warnings}}
+<p class="warning">⚠ {{.}}</p>
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- if .Text}}
+<p>{{.Text}}</p>
+{{- end}}
+{{- range .Warnings}}
+<p class="warning">⚠ {{.}}</p>
+{{- end}}
+{{- if .Fields}}
+<dl>
+{{- range .Fields}}
+<dt>{{.Label}}</dt><dd>{{if .Code}}<code>{{.Value}}</code>{{else}}{{.Value}}{{end}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+{{- if .Bars}}
+<div class="bars">
+{{- range .Bars}}
+<div>{{.Label}}</div><div class="bar{{if .Failed}} failed{{end}}"><span style="width: {{printf "%.1f" .Percent}}%"></span>{{.Value}}</div>
+{{- end}}
+</div>
+{{- end}}
+{{- with .Table}}
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Pre}}
+<pre>{{.Pre}}</pre>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// SaveHTML saves the benchmark results as an HTML report
+func SaveHTML(result *Result, filename string) error {
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteHTML(w, result)
+	})
+}
+
+// WriteHTML writes the benchmark results as an HTML report to w. It holds the
+// same information as the Markdown report, plus a chart of the run durations.
+func WriteHTML(w io.Writer, result *Result) error {
+	config := result.Config
+	histogram := NewHistogram(result.Durations())
+	report := HTMLReport{
+		Title:     "Caliper Benchmark Report: " + config.Name,
+		Generated: result.EndTime.Format(time.RFC1123),
+		Warnings:  resultWarnings(result, histogram),
+	}
+
+	// Configuration
+	fields := []HTMLField{{Label: "Command", Value: config.Command, Code: true}}
+	if config.CommandName != "" {
+		fields = append(fields, HTMLField{Label: "Command Name", Value: config.CommandName})
+	}
+	if config.MeasureStartup {
+		fields = append(fields, HTMLField{Label: "Measured", Value: fmt.Sprintf("startup until ready (%s, timeout %s)", config.Readiness.Description(), config.Readiness.Timeout)})
+	}
+	if config.Service != "" {
+		fields = append(fields, HTMLField{Label: "Service", Value: fmt.Sprintf("%s (ready after %s: %s)", config.Service, result.ServiceReady.Round(time.Millisecond), config.Readiness.Description())})
+	}
+	if config.Parameter.Name != "" {
+		fields = append(fields, HTMLField{Label: "Parameter", Value: config.Parameter.Name + " = " + config.Parameter.Value})
+	}
+	if config.Adaptive() {
+		target := fmt.Sprintf("±%.2f%% of %s (%d-%d runs", config.TargetCI*100, config.CIStatistic, config.MinRuns, config.MaxRuns)
+		if config.MaxTime > 0 {
+			target += fmt.Sprintf(", budget %s", config.MaxTime)
+		}
+		fields = append(fields, HTMLField{Label: "Target CI", Value: target + ")"})
+	}
+	fields = append(fields, HTMLField{Label: "Total Runs", Value: fmt.Sprintf("%d", len(result.Runs))})
+	if config.Timeout > 0 {
+		fields = append(fields, HTMLField{Label: "Run Timeout", Value: config.Timeout.String()})
+	}
+	fields = append(fields, HTMLField{Label: "Shell", Value: config.ShellName()})
+	if result.ShellOverhead > 0 {
+		fields = append(fields, HTMLField{Label: "Shell Overhead", Value: fmt.Sprintf("%s (subtracted from statistics)", result.ShellOverhead.Round(time.Microsecond))})
+	}
+	if config.Dir != "" {
+		fields = append(fields, HTMLField{Label: "Working Directory", Value: config.Dir, Code: true})
+	}
+	if env := formatEnvOverrides(config); env != "" {
+		fields = append(fields, HTMLField{Label: "Environment", Value: env, Code: true})
+	}
+	if len(config.ExpectedExitCodes) > 0 {
+		fields = append(fields, HTMLField{Label: "Expected Exit Codes", Value: formatExitCodes(config.ExpectedExitCodes)})
+	}
+	if config.IgnoreFailure {
+		fields = append(fields, HTMLField{Label: "Ignore Failures", Value: "every completed run is timed, whatever its exit code"})
+	}
+	if config.Retries > 0 {
+		retries := fmt.Sprintf("up to %d per run", config.Retries)
+		if len(config.RetryOnExitCodes) > 0 {
+			retries += " on exit codes " + formatExitCodes(config.RetryOnExitCodes)
+		}
+		fields = append(fields, HTMLField{Label: "Retries", Value: retries})
+	}
+	for _, hook := range []struct{ label, command string }{
+		{"Setup", config.Setup},
+		{"Prepare (each run, untimed)", config.Prepare},
+		{"Conclude (each run, untimed)", config.Conclude},
+		{"Cleanup", config.Cleanup},
+	} {
+		if hook.command != "" {
+			fields = append(fields, HTMLField{Label: hook.label, Value: hook.command, Code: true})
+		}
+	}
+	if len(result.WarmupRuns) > 0 {
+		fields = append(fields, HTMLField{Label: "Warm-up", Value: formatWarmupRuns(result) + " (excluded from stats)"})
+	} else {
+		fields = append(fields, HTMLField{Label: "Warm-up", Value: "Skipped"})
+	}
+	if len(result.Sessions) > 1 {
+		fields = append(fields, HTMLField{Label: "Resumed", Value: formatSessions(result)})
+	}
+	fields = append(fields,
+		HTMLField{Label: "Start Time", Value: result.StartTime.Format(time.RFC1123)},
+		HTMLField{Label: "End Time", Value: result.EndTime.Format(time.RFC1123)},
+		HTMLField{Label: "Total Duration", Value: result.TotalDuration.Round(time.Millisecond).String()},
+	)
+	report.Sections = append(report.Sections, HTMLSection{Title: "Configuration", Fields: fields})
+
+	// Summary
+	fields = []HTMLField{
+		{Label: "Successful Runs", Value: fmt.Sprintf("%d", result.SuccessfulRuns())},
+		{Label: "Failed Runs", Value: fmt.Sprintf("%d", len(result.Runs)-result.SuccessfulRuns())},
+	}
+	if timedOut := result.TimedOutRuns(); timedOut > 0 {
+		fields = append(fields, HTMLField{Label: "Timed Out Runs", Value: fmt.Sprintf("%d", timedOut)})
+	}
+	fields = append(fields, HTMLField{Label: "Success Rate", Value: fmt.Sprintf("%.1f%%", result.SuccessRate)})
+	if config.Retries > 0 {
+		fields = append(fields, HTMLField{Label: "Flakiness", Value: fmt.Sprintf("%.1f%% (runs that only succeeded after a retry)", result.Flakiness)})
+	}
+	if result.Stats.Outliers > 0 {
+		fields = append(fields, HTMLField{Label: "Outliers", Value: fmt.Sprintf("%d (%s)", result.Stats.Outliers, result.Stats.OutlierMethod.Description())})
+	}
+	if config.Adaptive() {
+		fields = append(fields,
+			HTMLField{Label: "Stopped", Value: result.StopReason.Description()},
+			HTMLField{Label: "Relative CI", Value: fmt.Sprintf("±%.2f%%", result.RelativeCI*100)},
+		)
+	}
+	if len(result.HostSamples) > 0 {
+		fields = append(fields, HTMLField{Label: "Host Noise", Value: formatHostNoise(result)})
+	}
+	fields = append(fields, HTMLField{Label: "Hook Failures", Value: fmt.Sprintf("%d", len(result.HookFailures))})
+	report.Sections = append(report.Sections, HTMLSection{Title: "Summary", Fields: fields})
+
+	if retried := result.RetriedRuns(); len(retried) > 0 {
+		table := &HTMLTable{Header: []string{"Run", "Attempts", "Outcome", "Failed Attempts"}}
+		for _, run := range retried {
+			outcome := "✓ " + formatDuration(run.Duration.Seconds())
+			if !run.Success {
+				outcome = "✗ failed"
+			}
+			var failures string
+			for i, attempt := range run.FailedAttempts {
+				failures += fmt.Sprintf("%d: %s\n", i+1, attempt.Error)
+			}
+			table.Rows = append(table.Rows, []string{fmt.Sprintf("%d", run.RunNumber), fmt.Sprintf("%d", run.Attempt), outcome, failures})
+		}
+		report.Sections = append(report.Sections, HTMLSection{
+			Title: "Retried Runs",
+			Text:  "Only the final attempt of each run is used in the statistics.",
+			Table: table,
+		})
+	}
+
+	if len(result.HookFailures) > 0 {
+		table := &HTMLTable{Header: []string{"Hook Failure"}}
+		for _, f := range result.HookFailures {
+			table.Rows = append(table.Rows, []string{formatHookFailure(f)})
+		}
+		report.Sections = append(report.Sections, HTMLSection{Title: "Hook Failures", Table: table})
+	}
+
+	// Statistics
+	if stats := result.Stats; stats.N > 0 {
+		text := "Statistics calculated from successful runs only."
+		if stats.Trimmed {
+			text = "Statistics calculated from successful runs, excluding outliers."
+		}
+		table := &HTMLTable{
+			Header: []string{"Metric", "Value", FormatConfidence(stats.Confidence) + " CI"},
+			Rows: [][]string{
+				{"N", fmt.Sprintf("%d", stats.N), ""},
+				{"Mean", formatDuration(stats.Mean), formatMeanCI(stats)},
+				{"Median", formatDuration(stats.Median), formatMedianCI(stats)},
+				{"Std Dev", formatDuration(stats.StdDev), ""},
+				{"CV", fmt.Sprintf("%.2f%%", stats.CV*100), ""},
+				{"MAD", formatDuration(stats.MAD), ""},
+				{"Min", formatDuration(stats.Min), ""},
+				{"Max", formatDuration(stats.Max), ""},
+				{"P90", formatDuration(stats.P90), ""},
+				{"P95", formatDuration(stats.P95), ""},
+			},
+		}
+		for _, p := range stats.Percentiles {
+			table.Rows = append(table.Rows, []string{p.Label(), formatDuration(p.Value), ""})
+		}
+		if stats.Trend.Valid() {
+			table.Rows = append(table.Rows, []string{"Trend", stats.Trend.String(), ""})
+		}
+		report.Sections = append(report.Sections, HTMLSection{Title: "Statistics", Text: text, Table: table})
+
+		if stats.N > 1 {
+			report.Sections = append(report.Sections, HTMLSection{
+				Title: "Distribution",
+				Text:  "Number of runs per duration range:",
+				Pre:   histogram.String(),
+			})
+		}
+
+		res := stats.Resources
+		report.Sections = append(report.Sections, HTMLSection{
+			Title: "Resource Usage",
+			Text:  "Mean resource usage of successful runs:",
+			Table: &HTMLTable{
+				Header: []string{"Metric", "Value"},
+				Rows: [][]string{
+					{"User CPU", formatDuration(res.MeanUserTime)},
+					{"System CPU", formatDuration(res.MeanSystemTime)},
+					{"Total CPU", formatDuration(res.MeanCPUTime)},
+					{"CPU Utilization", fmt.Sprintf("%.2f cores", res.CPUUtilization)},
+					{"Peak RSS (mean)", formatBytes(int64(res.MeanMaxRSS))},
+					{"Peak RSS (max)", formatBytes(res.PeakMaxRSS)},
+					{"Voluntary Ctx Switches", fmt.Sprintf("%.0f", res.MeanVoluntaryCtxSwitches)},
+					{"Involuntary Ctx Switches", fmt.Sprintf("%.0f", res.MeanInvoluntaryCtxSwitches)},
+					{"Minor Page Faults", fmt.Sprintf("%.0f", res.MeanMinorPageFaults)},
+					{"Major Page Faults", fmt.Sprintf("%.0f", res.MeanMajorPageFaults)},
+				},
+			},
+		})
+	}
+
+	if noisy := result.NoisyHostSamples(); len(noisy) > 0 {
+		table := &HTMLTable{Header: []string{"When", "Load", "Background CPU", "Steal", "I/O Wait", "Busy Processes"}}
+		for _, s := range noisy {
+			table.Rows = append(table.Rows, []string{
+				formatHostSampleTime(s),
+				fmt.Sprintf("%.2f", s.Load1),
+				fmt.Sprintf("%.1f of %d CPUs", s.BackgroundCPU, s.CPUs),
+				fmt.Sprintf("%.1f%%", s.Steal*100),
+				fmt.Sprintf("%.1f%%", s.IOWait*100),
+				formatBusyProcesses(s.BusyProcesses),
+			})
+		}
+		report.Sections = append(report.Sections, HTMLSection{
+			Title: "Host Noise",
+			Text:  "Samples of the background load that exceeded a threshold:",
+			Table: table,
+		})
+	}
+
+	// Individual runs, charted and listed
+	labels := make([]string, len(result.Runs))
+	values := make([]float64, len(result.Runs))
+	failed := make([]bool, len(result.Runs))
+	table := &HTMLTable{Header: []string{"Run", "Status", "Exit Code", "Duration", "CPU Time", "Peak RSS", "Error"}}
+	for i, run := range result.WarmupRuns {
+		table.Rows = append(table.Rows, htmlRunRow(fmt.Sprintf("warm-up %d", i+1), run))
+	}
+	for i, run := range result.Runs {
+		label := fmt.Sprintf("%d", run.RunNumber)
+		if len(result.Sessions) > 1 {
+			label += fmt.Sprintf(" (session %d)", run.Session)
+		}
+		labels[i] = "Run " + label
+		values[i] = run.Duration.Seconds()
+		failed[i] = !run.Success
+		table.Rows = append(table.Rows, htmlRunRow(label, run))
+	}
+	report.Sections = append(report.Sections, HTMLSection{
+		Title: "Individual Runs",
+		Text:  "Wall time of each measured run; failed runs in red.",
+		Bars:  NewHTMLBars(labels, values, failed),
+		Table: table,
+	})
+
+	return report.Write(w)
+}
+
+// htmlRunRow formats one row of the runs table of the HTML report
+func htmlRunRow(label string, run RunResult) []string {
+	errorCell := run.Error
+	if !run.Success && run.StderrTail != "" {
+		errorCell += "\n" + run.StderrTail
+	}
+	return []string{
+		label,
+		runStatusCell(run),
+		runExitCodeCell(run),
+		run.Duration.Round(time.Millisecond).String(),
+		run.Resources.CPUTime().Round(time.Millisecond).String(),
+		formatBytes(run.Resources.MaxRSS),
+		errorCell,
+	}
+}
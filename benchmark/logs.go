@@ -0,0 +1,183 @@
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Defaults for per-run output capture when they are not set explicitly
+const (
+	DefaultLogSizeLimit    = 10 << 20 // 10 MiB per stream and run
+	DefaultStderrTailLines = 10
+)
+
+// tailBufferSize bounds the memory used to keep the end of a run's stderr
+const tailBufferSize = 64 << 10
+
+// LogDir returns the directory that holds the per-run stdout/stderr logs
+func (c Config) LogDir() string {
+	name := c.Name
+	if name == "" {
+		name = "caliper"
+	}
+	return filepath.Join(c.OutputDir, name+"_logs")
+}
+
+// withLogDefaults fills in unset output capture settings
+func (c Config) withLogDefaults() Config {
+	if c.LogSizeLimit <= 0 {
+		c.LogSizeLimit = DefaultLogSizeLimit
+	}
+	if c.StderrTailLines <= 0 {
+		c.StderrTailLines = DefaultStderrTailLines
+	}
+	return c
+}
+
+// runOutput collects a run's stdout and stderr into capped log files and
+// keeps the tail of stderr in memory for failure excerpts
+type runOutput struct {
+	stdoutFile *os.File
+	stderrFile *os.File
+	stdoutLog  *cappedWriter
+	stderrLog  *cappedWriter
+	stderrTail *tailBuffer
+}
+
+// newRunOutput creates the log files for one run (or warm-up run) named logName.
+// Log files are only created when LogOutput is enabled.
+func newRunOutput(config Config, logName string) (*runOutput, error) {
+	out := &runOutput{stderrTail: &tailBuffer{}}
+	if !config.LogOutput || logName == "" {
+		return out, nil
+	}
+
+	dir := config.LogDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	var err error
+	out.stdoutFile, err = os.Create(filepath.Join(dir, logName+".stdout.log"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout log: %w", err)
+	}
+	out.stderrFile, err = os.Create(filepath.Join(dir, logName+".stderr.log"))
+	if err != nil {
+		out.stdoutFile.Close()
+		return nil, fmt.Errorf("failed to create stderr log: %w", err)
+	}
+
+	out.stdoutLog = &cappedWriter{w: out.stdoutFile, limit: config.LogSizeLimit}
+	out.stderrLog = &cappedWriter{w: out.stderrFile, limit: config.LogSizeLimit}
+	return out, nil
+}
+
+// stdout returns the writer for the command's stdout, also streaming it when debug is set
+func (o *runOutput) stdout(debug bool) io.Writer {
+	var writers []io.Writer
+	if debug {
+		writers = append(writers, os.Stdout)
+	}
+	if o.stdoutLog != nil {
+		writers = append(writers, o.stdoutLog)
+	}
+	switch len(writers) {
+	case 0:
+		return nil
+	case 1:
+		return writers[0]
+	default:
+		return io.MultiWriter(writers...)
+	}
+}
+
+// stderr returns the writer for the command's stderr, also streaming it when debug is set
+func (o *runOutput) stderr(debug bool) io.Writer {
+	writers := []io.Writer{o.stderrTail}
+	if debug {
+		writers = append(writers, os.Stderr)
+	}
+	if o.stderrLog != nil {
+		writers = append(writers, o.stderrLog)
+	}
+	return io.MultiWriter(writers...)
+}
+
+// close finishes the log files and records their paths on the run result
+func (o *runOutput) close(result *RunResult, tailLines int) {
+	result.StderrTail = o.stderrTail.lastLines(tailLines)
+
+	if o.stdoutFile != nil {
+		o.stdoutLog.finish()
+		o.stdoutFile.Close()
+		result.StdoutLog = o.stdoutFile.Name()
+	}
+	if o.stderrFile != nil {
+		o.stderrLog.finish()
+		o.stderrFile.Close()
+		result.StderrLog = o.stderrFile.Name()
+	}
+}
+
+// cappedWriter writes up to limit bytes and silently discards the rest, so a
+// chatty command can neither fill the disk nor block on a failed write
+type cappedWriter struct {
+	w         io.Writer
+	limit     int64
+	written   int64
+	truncated bool
+}
+
+func (c *cappedWriter) Write(p []byte) (int, error) {
+	remaining := c.limit - c.written
+	if int64(len(p)) > remaining {
+		c.truncated = true
+	}
+	if remaining > 0 {
+		chunk := p
+		if int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		n, _ := c.w.Write(chunk)
+		c.written += int64(n)
+	}
+	return len(p), nil
+}
+
+// finish appends a marker when output was dropped
+func (c *cappedWriter) finish() {
+	if c.truncated {
+		fmt.Fprintf(c.w, "\n[caliper: log truncated after %d bytes]\n", c.limit)
+	}
+}
+
+// tailBuffer keeps the last tailBufferSize bytes written to it
+type tailBuffer struct {
+	data []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.data = append(t.data, p...)
+	if len(t.data) > tailBufferSize {
+		t.data = append(t.data[:0], t.data[len(t.data)-tailBufferSize:]...)
+	}
+	return len(p), nil
+}
+
+// lastLines returns at most n trailing non-empty lines
+func (t *tailBuffer) lastLines(n int) string {
+	text := strings.TrimRight(string(t.data), "\n")
+	if text == "" || n <= 0 {
+		return ""
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,138 @@
+package benchmark
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OutlierMethod selects how outlier runs are detected
+type OutlierMethod string
+
+const (
+	OutlierIQR  OutlierMethod = "iqr"  // Tukey's fences: beyond 1.5 IQR outside the quartiles
+	OutlierMAD  OutlierMethod = "mad"  // Modified z-score above 3.5, based on the median absolute deviation
+	OutlierNone OutlierMethod = "none" // No outlier detection
+)
+
+// Outlier detection parameters
+const (
+	iqrFenceFactor     = 1.5
+	madZScoreThreshold = 3.5
+	madConsistency     = 0.6745 // Scales the MAD to the standard deviation of a normal distribution
+	minOutlierRuns     = 5      // Fewer successful runs are too few to call any of them an outlier
+)
+
+// ParseOutlierMethod parses an --outlier-method value
+func ParseOutlierMethod(value string) (OutlierMethod, error) {
+	switch method := OutlierMethod(strings.ToLower(value)); method {
+	case OutlierIQR, OutlierMAD, OutlierNone:
+		return method, nil
+	default:
+		return "", fmt.Errorf("invalid outlier method %q: expected iqr, mad or none", value)
+	}
+}
+
+// Description returns a human-readable name of the method
+func (m OutlierMethod) Description() string {
+	switch m {
+	case OutlierIQR:
+		return "1.5×IQR fences"
+	case OutlierMAD:
+		return "modified z-score > 3.5"
+	default:
+		return "disabled"
+	}
+}
+
+// outlierMethod returns the configured method, IQR by default
+func (c Config) outlierMethod() OutlierMethod {
+	if c.OutlierMethod == "" {
+		return OutlierIQR
+	}
+	return c.OutlierMethod
+}
+
+// OutlierFences returns the range of durations that are not outliers under
+// method. ok is false when there are too few durations or no spread to tell
+// outliers apart, in which case no duration is an outlier.
+func OutlierFences(durations []float64, method OutlierMethod) (lower, upper float64, ok bool) {
+	if len(durations) < minOutlierRuns || method == OutlierNone {
+		return 0, 0, false
+	}
+
+	sorted := make([]float64, len(durations))
+	copy(sorted, durations)
+	sort.Float64s(sorted)
+
+	switch method {
+	case OutlierMAD:
+		median := percentile(sorted, 50)
+		mad := medianAbsoluteDeviation(sorted)
+		if mad == 0 {
+			return 0, 0, false
+		}
+		spread := madZScoreThreshold * mad / madConsistency
+		return median - spread, median + spread, true
+	default:
+		q1 := percentile(sorted, 25)
+		q3 := percentile(sorted, 75)
+		iqr := q3 - q1
+		if iqr == 0 {
+			return 0, 0, false
+		}
+		return q1 - iqrFenceFactor*iqr, q3 + iqrFenceFactor*iqr, true
+	}
+}
+
+// flagOutliers marks the successful runs whose duration (in durations, one
+// per successful run in order) lies outside the fences, and returns the
+// durations of the remaining runs
+func flagOutliers(runs []RunResult, durations []float64, lower, upper float64) []float64 {
+	kept := make([]float64, 0, len(durations))
+	i := 0
+	for r := range runs {
+		if !runs[r].Success {
+			continue
+		}
+		d := durations[i]
+		i++
+		if d < lower || d > upper {
+			runs[r].Outlier = true
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return kept
+}
+
+// OutlierRuns returns the measured runs flagged as outliers
+func (r *Result) OutlierRuns() []RunResult {
+	var outliers []RunResult
+	for _, run := range r.Runs {
+		if run.Outlier {
+			outliers = append(outliers, run)
+		}
+	}
+	return outliers
+}
+
+// outlierWarning describes the flagged runs, e.g. "2 outliers (runs 4, 9) outside 1.02s–1.30s (1.5×IQR fences)"
+func outlierWarning(result *Result) string {
+	outliers := result.OutlierRuns()
+	numbers := make([]string, len(outliers))
+	for i, run := range outliers {
+		numbers[i] = fmt.Sprintf("%d", run.RunNumber)
+	}
+
+	noun, runs := "outliers", "runs"
+	if len(outliers) == 1 {
+		noun, runs = "outlier", "run"
+	}
+	warning := fmt.Sprintf("%d %s (%s %s) outside %s–%s (%s)", len(outliers), noun, runs, strings.Join(numbers, ", "),
+		formatShortDuration(result.Stats.LowerFence), formatShortDuration(result.Stats.UpperFence), result.Stats.OutlierMethod.Description())
+	if result.Stats.Trimmed {
+		return warning + ": excluded from the statistics"
+	}
+	return warning + ": included in the statistics, use --trim-outliers to exclude them"
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
@@ -17,32 +18,127 @@ func PrintConsole(result *Result) {
 	fmt.Printf("=================\n\n")
 
 	// Summary information
+	if result.Config.CommandName != "" {
+		fmt.Printf("Name:           %s\n", result.Config.CommandName)
+	}
 	fmt.Printf("Command:        %s\n", result.Config.Command)
-	fmt.Printf("Total Runs:     %d\n", result.Config.Runs)
-	if result.WarmupRun != nil {
-		fmt.Printf("Warm-up:        %v (excluded from stats)\n", result.WarmupRun.Duration.Round(time.Millisecond))
+	if result.Config.MeasureStartup {
+		fmt.Printf("Measured:       startup until ready (%s)\n", result.Config.Readiness.Description())
+	}
+	if result.Config.Service != "" {
+		fmt.Printf("Service:        %s (ready after %v)\n", result.Config.Service, result.ServiceReady.Round(time.Millisecond))
+	}
+	if result.Config.ShellName() != DefaultShell {
+		fmt.Printf("Shell:          %s\n", result.Config.ShellName())
+	}
+	if result.ShellOverhead > 0 {
+		fmt.Printf("Shell Overhead: %v (subtracted from statistics)\n", result.ShellOverhead.Round(time.Microsecond))
+	}
+	if result.Config.Dir != "" {
+		fmt.Printf("Working Dir:    %s\n", result.Config.Dir)
+	}
+	if env := formatEnvOverrides(result.Config); env != "" {
+		fmt.Printf("Environment:    %s\n", env)
+	}
+	if result.Config.Parameter.Name != "" {
+		fmt.Printf("Parameter:      %s = %s\n", result.Config.Parameter.Name, result.Config.Parameter.Value)
+	}
+	fmt.Printf("Total Runs:     %d\n", len(result.Runs))
+	if len(result.Sessions) > 1 {
+		fmt.Printf("Sessions:       %s\n", formatSessions(result))
+	}
+	if result.Config.Adaptive() {
+		fmt.Printf("Stopped:        %s\n", result.StopReason.Description())
+		fmt.Printf("Relative CI:    ±%.2f%% of %s (target ±%.2f%%)\n", result.RelativeCI*100, result.Config.CIStatistic, result.Config.TargetCI*100)
+	}
+	if len(result.WarmupRuns) > 0 {
+		fmt.Printf("Warm-up:        %s (excluded from stats)\n", formatWarmupRuns(result))
+	}
+	fmt.Printf("Successful:     %d\n", result.SuccessfulRuns())
+	fmt.Printf("Failed:         %d\n", len(result.Runs)-result.SuccessfulRuns())
+	if timedOut := result.TimedOutRuns(); timedOut > 0 {
+		fmt.Printf("Timed Out:      %d (limit %s)\n", timedOut, result.Config.runTimeout())
 	}
-	fmt.Printf("Successful:     %d\n", result.Stats.N)
-	fmt.Printf("Failed:         %d\n", result.Config.Runs-result.Stats.N)
 	fmt.Printf("Success Rate:   %.1f%%\n", result.SuccessRate)
+	if result.Stats.Outliers > 0 {
+		fmt.Printf("Outliers:       %d (%s)\n", result.Stats.Outliers, result.Stats.OutlierMethod.Description())
+	}
+	if retried := len(result.RetriedRuns()); retried > 0 {
+		fmt.Printf("Retried Runs:   %d (%d retries, flakiness %.1f%%)\n", retried, result.Retries(), result.Flakiness)
+	}
+	if len(result.HookFailures) > 0 {
+		fmt.Printf("Hook Failures:  %d\n", len(result.HookFailures))
+		for _, f := range result.HookFailures {
+			fmt.Printf("  - %s\n", formatHookFailure(f))
+		}
+	}
+	if len(result.HostSamples) > 0 {
+		fmt.Printf("Host Noise:     %s\n", formatHostNoise(result))
+	}
 	fmt.Printf("Total Duration: %v\n\n", result.TotalDuration.Round(time.Millisecond))
 
+	histogram := NewHistogram(result.Durations())
+	for _, warning := range resultWarnings(result, histogram) {
+		fmt.Printf("⚠ %s\n\n", warning)
+	}
+
 	// Statistics table
 	if result.Stats.N > 0 {
-		fmt.Printf("Statistics (successful runs only)\n")
-		fmt.Printf("---------------------------------\n\n")
+		if result.Stats.Trimmed {
+			fmt.Printf("Statistics (successful runs, outliers excluded)\n")
+			fmt.Printf("-----------------------------------------------\n\n")
+		} else {
+			fmt.Printf("Statistics (successful runs only)\n")
+			fmt.Printf("---------------------------------\n\n")
+		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Metric\tValue\t%s CI\n", FormatConfidence(result.Stats.Confidence))
+		fmt.Fprintf(w, "------\t-----\t------\n")
+		fmt.Fprintf(w, "N\t%d\t\n", result.Stats.N)
+		fmt.Fprintf(w, "Mean\t%s\t%s\n", formatDuration(result.Stats.Mean), formatMeanCI(result.Stats))
+		fmt.Fprintf(w, "Median\t%s\t%s\n", formatDuration(result.Stats.Median), formatMedianCI(result.Stats))
+		fmt.Fprintf(w, "Std Dev\t%s\t\n", formatDuration(result.Stats.StdDev))
+		fmt.Fprintf(w, "CV\t%.2f%%\t\n", result.Stats.CV*100)
+		fmt.Fprintf(w, "MAD\t%s\t\n", formatDuration(result.Stats.MAD))
+		fmt.Fprintf(w, "Min\t%s\t\n", formatDuration(result.Stats.Min))
+		fmt.Fprintf(w, "Max\t%s\t\n", formatDuration(result.Stats.Max))
+		fmt.Fprintf(w, "P90\t%s\t\n", formatDuration(result.Stats.P90))
+		fmt.Fprintf(w, "P95\t%s\t\n", formatDuration(result.Stats.P95))
+		for _, p := range result.Stats.Percentiles {
+			fmt.Fprintf(w, "%s\t%s\t\n", p.Label(), formatDuration(p.Value))
+		}
+		if result.Stats.Trend.Valid() {
+			fmt.Fprintf(w, "Trend\t%s\t\n", result.Stats.Trend)
+		}
+		w.Flush()
+
+		// Histogram of the durations
+		if result.Stats.N > 1 {
+			title := fmt.Sprintf("Distribution (%d bins)", len(histogram.Counts))
+			fmt.Printf("\n%s\n", title)
+			fmt.Printf("%s\n\n", strings.Repeat("-", len(title)))
+			fmt.Print(histogram.String())
+		}
+
+		// Resource usage table
+		res := result.Stats.Resources
+		fmt.Printf("\nResource Usage (mean of successful runs)\n")
+		fmt.Printf("----------------------------------------\n\n")
+
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 		fmt.Fprintf(w, "Metric\tValue\n")
 		fmt.Fprintf(w, "------\t-----\n")
-		fmt.Fprintf(w, "N\t%d\n", result.Stats.N)
-		fmt.Fprintf(w, "Mean\t%s\n", formatDuration(result.Stats.Mean))
-		fmt.Fprintf(w, "Median\t%s\n", formatDuration(result.Stats.Median))
-		fmt.Fprintf(w, "Std Dev\t%s\n", formatDuration(result.Stats.StdDev))
-		fmt.Fprintf(w, "Min\t%s\n", formatDuration(result.Stats.Min))
-		fmt.Fprintf(w, "Max\t%s\n", formatDuration(result.Stats.Max))
-		fmt.Fprintf(w, "P90\t%s\n", formatDuration(result.Stats.P90))
-		fmt.Fprintf(w, "P95\t%s\n", formatDuration(result.Stats.P95))
+		fmt.Fprintf(w, "User CPU\t%s\n", formatDuration(res.MeanUserTime))
+		fmt.Fprintf(w, "System CPU\t%s\n", formatDuration(res.MeanSystemTime))
+		fmt.Fprintf(w, "Total CPU\t%s\n", formatDuration(res.MeanCPUTime))
+		fmt.Fprintf(w, "CPU Utilization\t%.2f cores\n", res.CPUUtilization)
+		fmt.Fprintf(w, "Peak RSS (mean)\t%s\n", formatBytes(int64(res.MeanMaxRSS)))
+		fmt.Fprintf(w, "Peak RSS (max)\t%s\n", formatBytes(res.PeakMaxRSS))
+		fmt.Fprintf(w, "Voluntary Ctx Switches\t%.0f\n", res.MeanVoluntaryCtxSwitches)
+		fmt.Fprintf(w, "Involuntary Ctx Switches\t%.0f\n", res.MeanInvoluntaryCtxSwitches)
+		fmt.Fprintf(w, "Minor Page Faults\t%.0f\n", res.MeanMinorPageFaults)
+		fmt.Fprintf(w, "Major Page Faults\t%.0f\n", res.MeanMajorPageFaults)
 		w.Flush()
 	} else {
 		fmt.Printf("No successful runs to calculate statistics.\n")
@@ -51,94 +147,49 @@ func PrintConsole(result *Result) {
 
 // SaveJSON saves the benchmark results as JSON
 func SaveJSON(result *Result, filename string) error {
-	// Create a serializable version of the result
-	output := map[string]interface{}{
-		"config": map[string]interface{}{
-			"command":   result.Config.Command,
-			"runs":      result.Config.Runs,
-			"name":      result.Config.Name,
-			"outputDir": result.Config.OutputDir,
-		},
-		"summary": map[string]interface{}{
-			"totalRuns":     result.Config.Runs,
-			"successful":    result.Stats.N,
-			"failed":        result.Config.Runs - result.Stats.N,
-			"successRate":   result.SuccessRate,
-			"startTime":     result.StartTime.Format(time.RFC3339),
-			"endTime":       result.EndTime.Format(time.RFC3339),
-			"totalDuration": result.TotalDuration.Seconds(),
-		},
-		"statistics": map[string]interface{}{
-			"n":      result.Stats.N,
-			"mean":   result.Stats.Mean,
-			"median": result.Stats.Median,
-			"stdDev": result.Stats.StdDev,
-			"min":    result.Stats.Min,
-			"max":    result.Stats.Max,
-			"p90":    result.Stats.P90,
-			"p95":    result.Stats.P95,
-		},
-		"runs": result.Runs,
-	}
-
-	// Add warm-up run if present
-	if result.WarmupRun != nil {
-		output["warmupRun"] = map[string]interface{}{
-			"duration": result.WarmupRun.Duration.Seconds(),
-			"success":  result.WarmupRun.Success,
-			"error":    result.WarmupRun.Error,
-		}
-	}
-
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteJSON(w, result)
+	})
+}
 
-	encoder := json.NewEncoder(file)
+// WriteJSON writes the benchmark results as a ResultDocument to w
+func WriteJSON(w io.Writer, result *Result) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+	return encoder.Encode(NewResultDocument(result))
 }
 
 // SaveCSV saves the benchmark results as CSV
 func SaveCSV(result *Result, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteCSV(w, result)
+	})
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+// WriteCSV writes the benchmark results as CSV to w
+func WriteCSV(w io.Writer, result *Result) error {
+	writer := csv.NewWriter(w)
 
 	// Write header
-	if err := writer.Write([]string{"Run", "Success", "Duration (seconds)", "Error"}); err != nil {
+	header := []string{
+		"Run", "Success", "Status", "Exit Code", "Duration (seconds)", "Error", "Stderr Tail",
+		"User CPU (seconds)", "System CPU (seconds)", "Max RSS (bytes)",
+		"Voluntary Ctx Switches", "Involuntary Ctx Switches",
+		"Minor Page Faults", "Major Page Faults",
+		"Stdout Log", "Stderr Log", "Session", "Attempts", "Outlier",
+	}
+	if err := writer.Write(header); err != nil {
 		return err
 	}
 
-	// Write warm-up run if present
-	if result.WarmupRun != nil {
-		record := []string{
-			"warmup",
-			fmt.Sprintf("%t", result.WarmupRun.Success),
-			fmt.Sprintf("%.6f", result.WarmupRun.Duration.Seconds()),
-			result.WarmupRun.Error,
-		}
-		if err := writer.Write(record); err != nil {
+	// Write warm-up runs if present, then individual run results
+	for i, run := range result.WarmupRuns {
+		if err := writer.Write(csvRunRecord(fmt.Sprintf("warmup-%d", i+1), run)); err != nil {
 			return err
 		}
 	}
-
-	// Write individual run results
 	for _, run := range result.Runs {
-		record := []string{
-			fmt.Sprintf("%d", run.RunNumber),
-			fmt.Sprintf("%t", run.Success),
-			fmt.Sprintf("%.6f", run.Duration.Seconds()),
-			run.Error,
-		}
-		if err := writer.Write(record); err != nil {
+		if err := writer.Write(csvRunRecord(fmt.Sprintf("%d", run.RunNumber), run)); err != nil {
 			return err
 		}
 	}
@@ -151,23 +202,60 @@ func SaveCSV(result *Result, filename string) error {
 	writer.Write([]string{"Mean (seconds)", fmt.Sprintf("%.6f", result.Stats.Mean)})
 	writer.Write([]string{"Median (seconds)", fmt.Sprintf("%.6f", result.Stats.Median)})
 	writer.Write([]string{"Std Dev (seconds)", fmt.Sprintf("%.6f", result.Stats.StdDev)})
+	writer.Write([]string{"Coefficient of Variation", fmt.Sprintf("%.6f", result.Stats.CV)})
+	writer.Write([]string{"MAD (seconds)", fmt.Sprintf("%.6f", result.Stats.MAD)})
 	writer.Write([]string{"Min (seconds)", fmt.Sprintf("%.6f", result.Stats.Min)})
 	writer.Write([]string{"Max (seconds)", fmt.Sprintf("%.6f", result.Stats.Max)})
 	writer.Write([]string{"P90 (seconds)", fmt.Sprintf("%.6f", result.Stats.P90)})
 	writer.Write([]string{"P95 (seconds)", fmt.Sprintf("%.6f", result.Stats.P95)})
+	for _, p := range result.Stats.Percentiles {
+		writer.Write([]string{p.Label() + " (seconds)", fmt.Sprintf("%.6f", p.Value)})
+	}
+	writer.Write([]string{"Confidence Level", fmt.Sprintf("%g", result.Stats.Confidence)})
+	writer.Write([]string{"Mean t CI Low (seconds)", fmt.Sprintf("%.6f", result.Stats.MeanCI.Low)})
+	writer.Write([]string{"Mean t CI High (seconds)", fmt.Sprintf("%.6f", result.Stats.MeanCI.High)})
+	writer.Write([]string{"Mean Bootstrap CI Low (seconds)", fmt.Sprintf("%.6f", result.Stats.MeanBootstrapCI.Low)})
+	writer.Write([]string{"Mean Bootstrap CI High (seconds)", fmt.Sprintf("%.6f", result.Stats.MeanBootstrapCI.High)})
+	writer.Write([]string{"Median Bootstrap CI Low (seconds)", fmt.Sprintf("%.6f", result.Stats.MedianCI.Low)})
+	writer.Write([]string{"Median Bootstrap CI High (seconds)", fmt.Sprintf("%.6f", result.Stats.MedianCI.High)})
+	writer.Write([]string{"Trend Slope (seconds per run)", fmt.Sprintf("%.6f", result.Stats.Trend.Slope)})
+	writer.Write([]string{"Trend Drift", fmt.Sprintf("%.6f", result.Stats.Trend.Drift)})
+	writer.Write([]string{"Trend P-Value", fmt.Sprintf("%.6f", result.Stats.Trend.P)})
 	writer.Write([]string{"Success Rate (%)", fmt.Sprintf("%.1f", result.SuccessRate)})
+	writer.Write([]string{"Outliers", fmt.Sprintf("%d", result.Stats.Outliers)})
+	writer.Write([]string{"Outlier Method", string(result.Stats.OutlierMethod)})
+	writer.Write([]string{"Outliers Trimmed", fmt.Sprintf("%t", result.Stats.Trimmed)})
+	writer.Write([]string{"Flakiness (%)", fmt.Sprintf("%.1f", result.Flakiness)})
+	writer.Write([]string{"Retries", fmt.Sprintf("%d", result.Retries())})
+	writer.Write([]string{"Timed Out Runs", fmt.Sprintf("%d", result.TimedOutRuns())})
+	writer.Write([]string{"Hook Failures", fmt.Sprintf("%d", len(result.HookFailures))})
+	writer.Write([]string{"Noisy Host Samples", fmt.Sprintf("%d", len(result.NoisyHostSamples()))})
+	writer.Write([]string{"Shell Overhead (seconds)", fmt.Sprintf("%.6f", result.ShellOverhead.Seconds())})
+	writer.Write([]string{"Service Ready (seconds)", fmt.Sprintf("%.6f", result.ServiceReady.Seconds())})
+	writer.Write([]string{"Stop Reason", string(result.StopReason)})
+	writer.Write([]string{"Interrupted", fmt.Sprintf("%t", result.Interrupted)})
+	writer.Write([]string{"Relative CI (%)", fmt.Sprintf("%.2f", result.RelativeCI*100)})
+	writer.Write([]string{"Warm-up Runs", fmt.Sprintf("%d", len(result.WarmupRuns))})
+	writer.Write([]string{"Cold First Run", fmt.Sprintf("%t", result.ColdFirstRun)})
+	writer.Write([]string{"Mean User CPU (seconds)", fmt.Sprintf("%.6f", result.Stats.Resources.MeanUserTime)})
+	writer.Write([]string{"Mean System CPU (seconds)", fmt.Sprintf("%.6f", result.Stats.Resources.MeanSystemTime)})
+	writer.Write([]string{"CPU Utilization (cores)", fmt.Sprintf("%.3f", result.Stats.Resources.CPUUtilization)})
+	writer.Write([]string{"Mean Max RSS (bytes)", fmt.Sprintf("%.0f", result.Stats.Resources.MeanMaxRSS)})
+	writer.Write([]string{"Peak Max RSS (bytes)", fmt.Sprintf("%d", result.Stats.Resources.PeakMaxRSS)})
 
-	return nil
+	writer.Flush()
+	return writer.Error()
 }
 
 // SaveMarkdown saves the benchmark results as a Markdown report
 func SaveMarkdown(result *Result, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteMarkdown(w, result)
+	})
+}
 
+// WriteMarkdown writes the benchmark results as a Markdown report to w
+func WriteMarkdown(w io.Writer, result *Result) error {
 	var md strings.Builder
 
 	// Header
@@ -177,12 +265,72 @@ func SaveMarkdown(result *Result, filename string) error {
 	// Configuration
 	md.WriteString("## Configuration\n\n")
 	md.WriteString(fmt.Sprintf("- **Command:** `%s`\n", result.Config.Command))
+	if result.Config.CommandName != "" {
+		md.WriteString(fmt.Sprintf("- **Command Name:** %s\n", result.Config.CommandName))
+	}
+	if result.Config.MeasureStartup {
+		md.WriteString(fmt.Sprintf("- **Measured:** startup until ready (%s, timeout %s)\n", result.Config.Readiness.Description(), result.Config.Readiness.Timeout))
+	}
+	if result.Config.Service != "" {
+		md.WriteString(fmt.Sprintf("- **Service:** `%s` (ready after %s: %s)\n", result.Config.Service, result.ServiceReady.Round(time.Millisecond), result.Config.Readiness.Description()))
+	}
+	if result.Config.Parameter.Name != "" {
+		md.WriteString(fmt.Sprintf("- **Parameter:** `%s` = %s\n", result.Config.Parameter.Name, result.Config.Parameter.Value))
+	}
 	md.WriteString(fmt.Sprintf("- **Benchmark Name:** %s\n", result.Config.Name))
-	md.WriteString(fmt.Sprintf("- **Total Runs:** %d\n", result.Config.Runs))
-	if result.WarmupRun != nil {
-		md.WriteString(fmt.Sprintf("- **Warm-up Run:** %s (excluded from stats)\n", result.WarmupRun.Duration.Round(time.Millisecond)))
+	if result.Config.Adaptive() {
+		md.WriteString(fmt.Sprintf("- **Target CI:** ±%.2f%% of %s (%d-%d runs", result.Config.TargetCI*100, result.Config.CIStatistic, result.Config.MinRuns, result.Config.MaxRuns))
+		if result.Config.MaxTime > 0 {
+			md.WriteString(fmt.Sprintf(", budget %s", result.Config.MaxTime))
+		}
+		md.WriteString(")\n")
+	}
+	md.WriteString(fmt.Sprintf("- **Total Runs:** %d\n", len(result.Runs)))
+	if result.Config.Timeout > 0 {
+		md.WriteString(fmt.Sprintf("- **Run Timeout:** %s\n", result.Config.Timeout))
+	}
+	md.WriteString(fmt.Sprintf("- **Shell:** %s\n", result.Config.ShellName()))
+	if result.ShellOverhead > 0 {
+		md.WriteString(fmt.Sprintf("- **Shell Overhead:** %s (subtracted from statistics)\n", result.ShellOverhead.Round(time.Microsecond)))
+	}
+	if result.Config.Dir != "" {
+		md.WriteString(fmt.Sprintf("- **Working Directory:** `%s`\n", result.Config.Dir))
+	}
+	if env := formatEnvOverrides(result.Config); env != "" {
+		md.WriteString(fmt.Sprintf("- **Environment:** `%s`\n", env))
+	}
+	if len(result.Config.ExpectedExitCodes) > 0 {
+		md.WriteString(fmt.Sprintf("- **Expected Exit Codes:** %s\n", formatExitCodes(result.Config.ExpectedExitCodes)))
+	}
+	if result.Config.IgnoreFailure {
+		md.WriteString("- **Ignore Failures:** every completed run is timed, whatever its exit code\n")
+	}
+	if result.Config.Retries > 0 {
+		md.WriteString(fmt.Sprintf("- **Retries:** up to %d per run", result.Config.Retries))
+		if len(result.Config.RetryOnExitCodes) > 0 {
+			md.WriteString(fmt.Sprintf(" on exit codes %s", formatExitCodes(result.Config.RetryOnExitCodes)))
+		}
+		md.WriteString("\n")
+	}
+	if result.Config.Setup != "" {
+		md.WriteString(fmt.Sprintf("- **Setup:** `%s`\n", result.Config.Setup))
+	}
+	if result.Config.Prepare != "" {
+		md.WriteString(fmt.Sprintf("- **Prepare (each run, untimed):** `%s`\n", result.Config.Prepare))
+	}
+	if result.Config.Conclude != "" {
+		md.WriteString(fmt.Sprintf("- **Conclude (each run, untimed):** `%s`\n", result.Config.Conclude))
+	}
+	if result.Config.Cleanup != "" {
+		md.WriteString(fmt.Sprintf("- **Cleanup:** `%s`\n", result.Config.Cleanup))
+	}
+	if len(result.WarmupRuns) > 0 {
+		md.WriteString(fmt.Sprintf("- **Warm-up:** %s (excluded from stats)\n", formatWarmupRuns(result)))
 	} else {
-		md.WriteString("- **Warm-up Run:** Skipped\n")
+		md.WriteString("- **Warm-up:** Skipped\n")
+	}
+	if len(result.Sessions) > 1 {
+		md.WriteString(fmt.Sprintf("- **Resumed:** %s\n", formatSessions(result)))
 	}
 	md.WriteString(fmt.Sprintf("- **Start Time:** %s\n", result.StartTime.Format(time.RFC1123)))
 	md.WriteString(fmt.Sprintf("- **End Time:** %s\n", result.EndTime.Format(time.RFC1123)))
@@ -190,51 +338,360 @@ func SaveMarkdown(result *Result, filename string) error {
 
 	// Summary
 	md.WriteString("## Summary\n\n")
-	md.WriteString(fmt.Sprintf("- **Successful Runs:** %d\n", result.Stats.N))
-	md.WriteString(fmt.Sprintf("- **Failed Runs:** %d\n", result.Config.Runs-result.Stats.N))
-	md.WriteString(fmt.Sprintf("- **Success Rate:** %.1f%%\n\n", result.SuccessRate))
+	md.WriteString(fmt.Sprintf("- **Successful Runs:** %d\n", result.SuccessfulRuns()))
+	md.WriteString(fmt.Sprintf("- **Failed Runs:** %d\n", len(result.Runs)-result.SuccessfulRuns()))
+	if timedOut := result.TimedOutRuns(); timedOut > 0 {
+		md.WriteString(fmt.Sprintf("- **Timed Out Runs:** %d\n", timedOut))
+	}
+	md.WriteString(fmt.Sprintf("- **Success Rate:** %.1f%%\n", result.SuccessRate))
+	if result.Config.Retries > 0 {
+		md.WriteString(fmt.Sprintf("- **Flakiness:** %.1f%% (runs that only succeeded after a retry)\n", result.Flakiness))
+	}
+	if result.Stats.Outliers > 0 {
+		md.WriteString(fmt.Sprintf("- **Outliers:** %d (%s)\n", result.Stats.Outliers, result.Stats.OutlierMethod.Description()))
+	}
+	if result.Config.Adaptive() {
+		md.WriteString(fmt.Sprintf("- **Stopped:** %s\n", result.StopReason.Description()))
+		md.WriteString(fmt.Sprintf("- **Relative CI:** ±%.2f%%\n", result.RelativeCI*100))
+	}
+	if len(result.HostSamples) > 0 {
+		md.WriteString(fmt.Sprintf("- **Host Noise:** %s\n", formatHostNoise(result)))
+	}
+	md.WriteString(fmt.Sprintf("- **Hook Failures:** %d\n\n", len(result.HookFailures)))
+
+	histogram := NewHistogram(result.Durations())
+	for _, warning := range resultWarnings(result, histogram) {
+		md.WriteString(fmt.Sprintf("> ⚠ %s\n\n", warning))
+	}
+
+	if retried := result.RetriedRuns(); len(retried) > 0 {
+		md.WriteString("## Retried Runs\n\n")
+		md.WriteString("Only the final attempt of each run is used in the statistics.\n\n")
+		md.WriteString("| Run | Attempts | Outcome | Failed Attempts |\n")
+		md.WriteString("|-----|----------|---------|-----------------|\n")
+		for _, run := range retried {
+			outcome := "✓ " + formatDuration(run.Duration.Seconds())
+			if !run.Success {
+				outcome = "✗ failed"
+			}
+			failures := make([]string, len(run.FailedAttempts))
+			for i, attempt := range run.FailedAttempts {
+				failures[i] = fmt.Sprintf("%d: %s", i+1, markdownErrorCell(attempt))
+			}
+			md.WriteString(fmt.Sprintf("| %d | %d | %s | %s |\n", run.RunNumber, run.Attempt, outcome, strings.Join(failures, "<br>")))
+		}
+		md.WriteString("\n")
+	}
+
+	if len(result.HookFailures) > 0 {
+		md.WriteString("## Hook Failures\n\n")
+		for _, f := range result.HookFailures {
+			md.WriteString(fmt.Sprintf("- %s\n", formatHookFailure(f)))
+		}
+		md.WriteString("\n")
+	}
 
 	// Statistics
 	if result.Stats.N > 0 {
 		md.WriteString("## Statistics\n\n")
-		md.WriteString("Statistics calculated from successful runs only:\n\n")
+		if result.Stats.Trimmed {
+			md.WriteString("Statistics calculated from successful runs, excluding outliers:\n\n")
+		} else {
+			md.WriteString("Statistics calculated from successful runs only:\n\n")
+		}
+		md.WriteString(fmt.Sprintf("| Metric | Value | %s CI |\n", FormatConfidence(result.Stats.Confidence)))
+		md.WriteString("|--------|-------|--------|\n")
+		md.WriteString(fmt.Sprintf("| N | %d | |\n", result.Stats.N))
+		md.WriteString(fmt.Sprintf("| Mean | %s | %s |\n", formatDuration(result.Stats.Mean), formatMeanCI(result.Stats)))
+		md.WriteString(fmt.Sprintf("| Median | %s | %s |\n", formatDuration(result.Stats.Median), formatMedianCI(result.Stats)))
+		md.WriteString(fmt.Sprintf("| Std Dev | %s | |\n", formatDuration(result.Stats.StdDev)))
+		md.WriteString(fmt.Sprintf("| CV | %.2f%% | |\n", result.Stats.CV*100))
+		md.WriteString(fmt.Sprintf("| MAD | %s | |\n", formatDuration(result.Stats.MAD)))
+		md.WriteString(fmt.Sprintf("| Min | %s | |\n", formatDuration(result.Stats.Min)))
+		md.WriteString(fmt.Sprintf("| Max | %s | |\n", formatDuration(result.Stats.Max)))
+		md.WriteString(fmt.Sprintf("| P90 | %s | |\n", formatDuration(result.Stats.P90)))
+		md.WriteString(fmt.Sprintf("| P95 | %s | |\n", formatDuration(result.Stats.P95)))
+		for _, p := range result.Stats.Percentiles {
+			md.WriteString(fmt.Sprintf("| %s | %s | |\n", p.Label(), formatDuration(p.Value)))
+		}
+		if result.Stats.Trend.Valid() {
+			md.WriteString(fmt.Sprintf("| Trend | %s | |\n", result.Stats.Trend))
+		}
+		md.WriteString("\n")
+		md.WriteString("Std Dev is the sample standard deviation (N−1), CV is Std Dev / Mean, MAD is the median absolute deviation from the median, and percentiles interpolate linearly between the closest runs.\n\n")
+		if result.Stats.Trend.Valid() {
+			md.WriteString("The trend is the least-squares line of duration versus run number, tested for a zero slope with a t-test.\n\n")
+		}
+		if result.Stats.MeanCI.Valid() {
+			md.WriteString(fmt.Sprintf("Confidence intervals of the mean from Student's t-distribution and a bootstrap of %d resamples, of the median from the bootstrap.\n\n", bootstrapResamples))
+		}
+
+		if result.Stats.N > 1 {
+			md.WriteString("## Distribution\n\n")
+			md.WriteString("Number of runs per duration range:\n\n")
+			md.WriteString("```\n")
+			md.WriteString(histogram.String())
+			md.WriteString("```\n\n")
+		}
+
+		res := result.Stats.Resources
+		md.WriteString("## Resource Usage\n\n")
+		md.WriteString("Mean resource usage of successful runs:\n\n")
 		md.WriteString("| Metric | Value |\n")
 		md.WriteString("|--------|-------|\n")
-		md.WriteString(fmt.Sprintf("| N | %d |\n", result.Stats.N))
-		md.WriteString(fmt.Sprintf("| Mean | %s |\n", formatDuration(result.Stats.Mean)))
-		md.WriteString(fmt.Sprintf("| Median | %s |\n", formatDuration(result.Stats.Median)))
-		md.WriteString(fmt.Sprintf("| Std Dev | %s |\n", formatDuration(result.Stats.StdDev)))
-		md.WriteString(fmt.Sprintf("| Min | %s |\n", formatDuration(result.Stats.Min)))
-		md.WriteString(fmt.Sprintf("| Max | %s |\n", formatDuration(result.Stats.Max)))
-		md.WriteString(fmt.Sprintf("| P90 | %s |\n", formatDuration(result.Stats.P90)))
-		md.WriteString(fmt.Sprintf("| P95 | %s |\n", formatDuration(result.Stats.P95)))
+		md.WriteString(fmt.Sprintf("| User CPU | %s |\n", formatDuration(res.MeanUserTime)))
+		md.WriteString(fmt.Sprintf("| System CPU | %s |\n", formatDuration(res.MeanSystemTime)))
+		md.WriteString(fmt.Sprintf("| Total CPU | %s |\n", formatDuration(res.MeanCPUTime)))
+		md.WriteString(fmt.Sprintf("| CPU Utilization | %.2f cores |\n", res.CPUUtilization))
+		md.WriteString(fmt.Sprintf("| Peak RSS (mean) | %s |\n", formatBytes(int64(res.MeanMaxRSS))))
+		md.WriteString(fmt.Sprintf("| Peak RSS (max) | %s |\n", formatBytes(res.PeakMaxRSS)))
+		md.WriteString(fmt.Sprintf("| Voluntary Ctx Switches | %.0f |\n", res.MeanVoluntaryCtxSwitches))
+		md.WriteString(fmt.Sprintf("| Involuntary Ctx Switches | %.0f |\n", res.MeanInvoluntaryCtxSwitches))
+		md.WriteString(fmt.Sprintf("| Minor Page Faults | %.0f |\n", res.MeanMinorPageFaults))
+		md.WriteString(fmt.Sprintf("| Major Page Faults | %.0f |\n", res.MeanMajorPageFaults))
+		md.WriteString("\n")
+	}
+
+	if noisy := result.NoisyHostSamples(); len(noisy) > 0 {
+		md.WriteString("## Host Noise\n\n")
+		md.WriteString("Samples of the background load that exceeded a threshold:\n\n")
+		md.WriteString("| When | Load | Background CPU | Steal | I/O Wait | Busy Processes |\n")
+		md.WriteString("|------|------|----------------|-------|----------|----------------|\n")
+		for _, s := range noisy {
+			md.WriteString(fmt.Sprintf("| %s | %.2f | %.1f of %d CPUs | %.1f%% | %.1f%% | %s |\n",
+				formatHostSampleTime(s), s.Load1, s.BackgroundCPU, s.CPUs, s.Steal*100, s.IOWait*100, formatBusyProcesses(s.BusyProcesses)))
+		}
 		md.WriteString("\n")
 	}
 
 	// Individual runs
 	md.WriteString("## Individual Runs\n\n")
-	md.WriteString("| Run | Status | Duration | Error |\n")
-	md.WriteString("|-----|--------|----------|-------|\n")
+	md.WriteString("| Run | Status | Exit Code | Duration | CPU Time | Peak RSS | Error |\n")
+	md.WriteString("|-----|--------|-----------|----------|----------|----------|-------|\n")
+	for i, run := range result.WarmupRuns {
+		md.WriteString(markdownRunRow(fmt.Sprintf("warm-up %d", i+1), run))
+	}
 	for _, run := range result.Runs {
-		status := "✓"
-		if !run.Success {
-			status = "✗"
-		}
-		errorMsg := ""
-		if run.Error != "" {
-			errorMsg = run.Error
+		label := fmt.Sprintf("%d", run.RunNumber)
+		if len(result.Sessions) > 1 {
+			label += fmt.Sprintf(" (session %d)", run.Session)
 		}
-		md.WriteString(fmt.Sprintf("| %d | %s | %s | %s |\n",
-			run.RunNumber,
-			status,
-			run.Duration.Round(time.Millisecond),
-			errorMsg))
+		md.WriteString(markdownRunRow(label, run))
 	}
 
-	_, err = file.WriteString(md.String())
+	_, err := io.WriteString(w, md.String())
 	return err
 }
 
+// resultWarnings returns the warnings about a result, in the order the reports show them
+func resultWarnings(result *Result, histogram Histogram) []string {
+	var warnings []string
+	if result.Interrupted {
+		warnings = append(warnings, interruptedWarning(result))
+	}
+	if result.ColdFirstRun {
+		warnings = append(warnings, coldFirstRunWarning(result))
+	}
+	if result.Stats.Outliers > 0 {
+		warnings = append(warnings, outlierWarning(result))
+	}
+	if result.Stats.Trend.Drifting() {
+		warnings = append(warnings, DriftWarning(result.Stats.Trend))
+	}
+	if histogram.MultiModal() {
+		warnings = append(warnings, MultiModalWarning(histogram))
+	}
+	if affected := noisyRunSamples(result); len(affected) > 0 {
+		warnings = append(warnings, hostNoiseWarning(result, affected))
+	}
+	return warnings
+}
+
+// interruptedWarning explains that an interrupted benchmark only reports its completed runs
+func interruptedWarning(result *Result) string {
+	if result.Config.Adaptive() {
+		return fmt.Sprintf("Interrupted after %d completed runs: statistics cover these runs only", len(result.Runs))
+	}
+	return fmt.Sprintf("Interrupted after %d of %d runs: statistics cover the completed runs only", len(result.Runs), result.Config.Runs)
+}
+
+// formatExitCodes formats a list of exit codes like "0, 1"
+func formatExitCodes(codes []int) string {
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = fmt.Sprintf("%d", code)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// markdownRunRow formats one row of the Individual Runs table
+func markdownRunRow(label string, run RunResult) string {
+	return fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+		label,
+		runStatusCell(run),
+		runExitCodeCell(run),
+		run.Duration.Round(time.Millisecond),
+		run.Resources.CPUTime().Round(time.Millisecond),
+		formatBytes(run.Resources.MaxRSS),
+		markdownErrorCell(run))
+}
+
+// runStatusCell formats the status of a run for the runs tables of the reports
+func runStatusCell(run RunResult) string {
+	switch {
+	case run.Status == RunStatusTimedOut:
+		return "⏱ timed out"
+	case run.Status == RunStatusHookFailed:
+		return "✗ hook"
+	case !run.Success:
+		return "✗"
+	case run.Outlier:
+		return "✓ outlier"
+	}
+	return "✓"
+}
+
+// runExitCodeCell formats the exit code of a run, "-" when killed or not started
+func runExitCodeCell(run RunResult) string {
+	if run.ExitCode < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", run.ExitCode)
+}
+
+// markdownErrorCell formats a run's error and, for failed runs, its stderr excerpt
+// as a single table cell
+func markdownErrorCell(run RunResult) string {
+	cell := strings.ReplaceAll(run.Error, "|", "\\|")
+	if run.Success || run.StderrTail == "" {
+		return cell
+	}
+
+	escaper := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "&#124;")
+	for _, line := range strings.Split(run.StderrTail, "\n") {
+		cell += "<br><code>" + escaper.Replace(line) + "</code>"
+	}
+	return cell
+}
+
+// csvRunRecord formats one row of the per-run CSV section
+func csvRunRecord(label string, run RunResult) []string {
+	record := []string{
+		label,
+		fmt.Sprintf("%t", run.Success),
+		string(run.Status),
+		fmt.Sprintf("%d", run.ExitCode),
+		fmt.Sprintf("%.6f", run.Duration.Seconds()),
+		run.Error,
+		run.StderrTail,
+	}
+	record = append(record, resourceRecord(run.Resources)...)
+	return append(record, run.StdoutLog, run.StderrLog, fmt.Sprintf("%d", run.Session), fmt.Sprintf("%d", run.Attempt), fmt.Sprintf("%t", run.Outlier))
+}
+
+// sessionDocuments converts the sessions to their JSON documents
+func sessionDocuments(sessions []Session) []SessionDocument {
+	out := make([]SessionDocument, 0, len(sessions))
+	for _, session := range sessions {
+		out = append(out, SessionDocument{
+			Number:    session.Number,
+			StartTime: session.StartTime.Format(time.RFC3339),
+			Runs:      session.Runs,
+		})
+	}
+	return out
+}
+
+// formatWarmupRuns summarises the warm-up runs, e.g. "3 runs (auto, stable): 12.1s, 8.4s, 8.3s"
+func formatWarmupRuns(result *Result) string {
+	durations := make([]string, 0, len(result.WarmupRuns))
+	for _, run := range result.WarmupRuns {
+		durations = append(durations, run.Duration.Round(time.Millisecond).String())
+	}
+
+	if len(result.WarmupRuns) == 1 && !result.Config.WarmupAuto {
+		return durations[0]
+	}
+
+	mode := ""
+	if result.Config.WarmupAuto {
+		if result.WarmupStable {
+			mode = " (auto, stable)"
+		} else {
+			mode = " (auto, not stable)"
+		}
+	}
+	return fmt.Sprintf("%d runs%s: %s", len(result.WarmupRuns), mode, strings.Join(durations, ", "))
+}
+
+// coldFirstRunWarning explains why the first measured run was flagged
+func coldFirstRunWarning(result *Result) string {
+	return fmt.Sprintf("Run 1 (%s) is much slower than the other runs (median %s); it may still be cold. Consider more warm-up runs (--warmup N or --warmup auto).",
+		result.Runs[0].Duration.Round(time.Millisecond), formatShortDuration(result.Stats.Median))
+}
+
+// formatHostNoise summarizes the host samples, e.g. "2 of 11 samples noisy"
+func formatHostNoise(result *Result) string {
+	noisy := len(result.NoisyHostSamples())
+	if noisy == 0 {
+		return fmt.Sprintf("quiet (%d samples)", len(result.HostSamples))
+	}
+	return fmt.Sprintf("%d of %d samples noisy", noisy, len(result.HostSamples))
+}
+
+// noisyRunSamples returns the noisy host samples that may have slowed runs
+// down. With --require-quiet-host, noise between runs was waited out.
+func noisyRunSamples(result *Result) []HostSample {
+	var affected []HostSample
+	for _, s := range result.NoisyHostSamples() {
+		if s.Run > 0 || !result.Config.RequireQuietHost {
+			affected = append(affected, s)
+		}
+	}
+	return affected
+}
+
+// hostNoiseWarning explains which runs may have been slowed down by background load
+func hostNoiseWarning(result *Result, noisy []HostSample) string {
+	var when []string
+	for _, s := range noisy {
+		if label := formatHostSampleTime(s); len(when) == 0 || when[len(when)-1] != label {
+			when = append(when, label)
+		}
+	}
+	advice := "rerun on a quiet host or with --require-quiet-host"
+	if result.Config.RequireQuietHost {
+		advice = "the host was quiet before each run, so the load started while they ran"
+	}
+	return fmt.Sprintf("The host was busy (%s): %s. These runs may be slower than usual; %s.",
+		strings.Join(when, ", "), noisy[0].Description(), advice)
+}
+
+// formatHostSampleTime describes when a host sample was taken, e.g. "run 3"
+func formatHostSampleTime(s HostSample) string {
+	if s.Run == 0 {
+		return "between runs"
+	}
+	return fmt.Sprintf("run %d", s.Run)
+}
+
+// formatBusyProcesses formats busy processes like "cc1plus (1.2 CPUs), chrome (0.6 CPUs)"
+func formatBusyProcesses(processes []BusyProcess) string {
+	if len(processes) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(processes))
+	for i, p := range processes {
+		parts[i] = fmt.Sprintf("%s (%.1f CPUs)", p.Name, p.CPU)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MultiModalWarning explains why a multi-modal distribution makes the statistics misleading
+func MultiModalWarning(histogram Histogram) string {
+	return fmt.Sprintf("The durations look multi-modal (%d peaks): the runs fall into separate groups, e.g. with and without a warm cache. The mean and median describe none of them; see the distribution.", histogram.Modes())
+}
+
 // formatDuration formats a duration in seconds to a human-readable string
 func formatDuration(seconds float64) string {
 	duration := time.Duration(seconds * float64(time.Second))
@@ -248,3 +705,471 @@ func formatDuration(seconds float64) string {
 
 	return fmt.Sprintf("%s (%.3fs)", duration, seconds)
 }
+
+// hostSamplesJSON returns the host samples, or an empty list rather than null
+func hostSamplesJSON(samples []HostSample) []HostSample {
+	if samples == nil {
+		return []HostSample{}
+	}
+	return samples
+}
+
+// percentilesJSON returns the requested percentiles, as an empty list rather than null when there are none
+func percentilesJSON(percentiles []Percentile) []Percentile {
+	if percentiles == nil {
+		return []Percentile{}
+	}
+	return percentiles
+}
+
+// formatMeanCI formats the t-distribution and bootstrap intervals of the mean,
+// e.g. "1.021s–1.094s (t), 1.025s–1.090s (bootstrap)"
+func formatMeanCI(stats Statistics) string {
+	if !stats.MeanCI.Valid() {
+		return "-"
+	}
+	return fmt.Sprintf("%s (t), %s (bootstrap)", stats.MeanCI, stats.MeanBootstrapCI)
+}
+
+// formatMedianCI formats the bootstrap interval of the median
+func formatMedianCI(stats Statistics) string {
+	if !stats.MedianCI.Valid() {
+		return "-"
+	}
+	return fmt.Sprintf("%s (bootstrap)", stats.MedianCI)
+}
+
+// PrintComparison outputs the relative speed of several commands to the console
+func PrintComparison(comparison *Comparison) {
+	fmt.Printf("\n")
+	fmt.Printf("Comparison\n")
+	fmt.Printf("==========\n\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Command\tMean ± σ\tMin … Max\tSuccess\tRelative\n")
+	fmt.Fprintf(w, "-------\t--------\t---------\t-------\t--------\n")
+	for i, r := range comparison.Results {
+		if r.Stats.N == 0 {
+			fmt.Fprintf(w, "%s\tFAILED\t-\t%.0f%%\t-\n", r.Config.Label(), r.SuccessRate)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s ± %s\t%s … %s\t%.0f%%\t%s\n",
+			r.Config.Label(),
+			formatShortDuration(r.Stats.Mean),
+			formatShortDuration(r.Stats.StdDev),
+			formatShortDuration(r.Stats.Min),
+			formatShortDuration(r.Stats.Max),
+			r.SuccessRate,
+			formatRelative(comparison.Relative[i]),
+		)
+	}
+	w.Flush()
+
+	if summary := comparisonSummary(comparison); len(summary) > 0 {
+		fmt.Printf("\nSummary\n")
+		for _, line := range summary {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// SaveComparisonJSON saves the relative speed of several commands as JSON
+func SaveComparisonJSON(comparison *Comparison, filename string) error {
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteComparisonJSON(w, comparison)
+	})
+}
+
+// WriteComparisonJSON writes the relative speed of several commands as JSON to w
+func WriteComparisonJSON(w io.Writer, comparison *Comparison) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewComparisonDocument(comparison))
+}
+
+// SaveComparisonMarkdown saves the relative speed of several commands as a Markdown report
+func SaveComparisonMarkdown(comparison *Comparison, filename string) error {
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteComparisonMarkdown(w, comparison)
+	})
+}
+
+// WriteComparisonMarkdown writes the relative speed of several commands as a Markdown report to w
+func WriteComparisonMarkdown(w io.Writer, comparison *Comparison) error {
+	var md strings.Builder
+
+	md.WriteString("# Caliper Comparison Report\n\n")
+	md.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format(time.RFC1123)))
+
+	md.WriteString("## Results\n\n")
+	md.WriteString("| Command | Mean ± σ | Median | Min | Max | Success Rate | Relative |\n")
+	md.WriteString("|---------|----------|--------|-----|-----|--------------|----------|\n")
+	for i, r := range comparison.Results {
+		if r.Stats.N == 0 {
+			md.WriteString(fmt.Sprintf("| `%s` | FAILED | - | - | - | %.1f%% | - |\n", r.Config.Label(), r.SuccessRate))
+			continue
+		}
+		md.WriteString(fmt.Sprintf("| `%s` | %s ± %s | %s | %s | %s | %.1f%% | %s |\n",
+			r.Config.Label(),
+			formatShortDuration(r.Stats.Mean),
+			formatShortDuration(r.Stats.StdDev),
+			formatShortDuration(r.Stats.Median),
+			formatShortDuration(r.Stats.Min),
+			formatShortDuration(r.Stats.Max),
+			r.SuccessRate,
+			formatRelative(comparison.Relative[i]),
+		))
+	}
+	md.WriteString("\n")
+
+	if summary := comparisonSummary(comparison); len(summary) > 0 {
+		md.WriteString("## Summary\n\n")
+		for _, line := range summary {
+			md.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+		md.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, md.String())
+	return err
+}
+
+// PrintScan outputs the summary of a parameter scan to the console
+func PrintScan(scan *ScanResult) {
+	comparison := Compare(scan.Results)
+
+	title := fmt.Sprintf("Parameter Scan: %s", scan.Parameter)
+	fmt.Printf("\n")
+	fmt.Printf("%s\n", title)
+	fmt.Printf("%s\n\n", strings.Repeat("=", len(title)))
+	fmt.Printf("Command: %s\n\n", scan.Command)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tMean ± σ\tMin … Max\tSuccess\tRelative\n", scan.Parameter)
+	fmt.Fprintf(w, "%s\t--------\t---------\t-------\t--------\n", strings.Repeat("-", len(scan.Parameter)))
+	for i, r := range scan.Results {
+		if r.Stats.N == 0 {
+			fmt.Fprintf(w, "%s\tFAILED\t-\t%.0f%%\t-\n", r.Config.Parameter.Value, r.SuccessRate)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s ± %s\t%s … %s\t%.0f%%\t%s\n",
+			r.Config.Parameter.Value,
+			formatShortDuration(r.Stats.Mean),
+			formatShortDuration(r.Stats.StdDev),
+			formatShortDuration(r.Stats.Min),
+			formatShortDuration(r.Stats.Max),
+			r.SuccessRate,
+			formatRelative(comparison.Relative[i]),
+		)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%s", scanChartString(scan))
+}
+
+// SaveScanJSON saves the summary of a parameter scan as JSON
+func SaveScanJSON(scan *ScanResult, filename string) error {
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteScanJSON(w, scan)
+	})
+}
+
+// WriteScanJSON writes the summary of a parameter scan as JSON to w
+func WriteScanJSON(w io.Writer, scan *ScanResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewScanDocument(scan))
+}
+
+// SaveScanMarkdown saves the summary of a parameter scan as a Markdown report
+func SaveScanMarkdown(scan *ScanResult, filename string) error {
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteScanMarkdown(w, scan)
+	})
+}
+
+// WriteScanMarkdown writes the summary of a parameter scan as a Markdown report to w
+func WriteScanMarkdown(w io.Writer, scan *ScanResult) error {
+	comparison := Compare(scan.Results)
+
+	var md strings.Builder
+
+	md.WriteString("# Caliper Parameter Scan Report\n\n")
+	md.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format(time.RFC1123)))
+
+	md.WriteString("## Configuration\n\n")
+	md.WriteString(fmt.Sprintf("- **Command:** `%s`\n", scan.Command))
+	md.WriteString(fmt.Sprintf("- **Parameter:** `%s`\n", scan.Parameter))
+	md.WriteString(fmt.Sprintf("- **Values:** %d\n\n", len(scan.Results)))
+
+	md.WriteString("## Results\n\n")
+	md.WriteString(fmt.Sprintf("| %s | Mean ± σ | Median | Min | Max | Success Rate | Relative |\n", scan.Parameter))
+	md.WriteString("|---|----------|--------|-----|-----|--------------|----------|\n")
+	for i, r := range scan.Results {
+		if r.Stats.N == 0 {
+			md.WriteString(fmt.Sprintf("| %s | FAILED | - | - | - | %.1f%% | - |\n", r.Config.Parameter.Value, r.SuccessRate))
+			continue
+		}
+		md.WriteString(fmt.Sprintf("| %s | %s ± %s | %s | %s | %s | %.1f%% | %s |\n",
+			r.Config.Parameter.Value,
+			formatShortDuration(r.Stats.Mean),
+			formatShortDuration(r.Stats.StdDev),
+			formatShortDuration(r.Stats.Median),
+			formatShortDuration(r.Stats.Min),
+			formatShortDuration(r.Stats.Max),
+			r.SuccessRate,
+			formatRelative(comparison.Relative[i]),
+		))
+	}
+	md.WriteString("\n")
+
+	if chart := scanChartString(scan); chart != "" {
+		md.WriteString("## Graph\n\n")
+		md.WriteString("```\n")
+		md.WriteString(chart)
+		md.WriteString("```\n")
+	}
+
+	_, err := io.WriteString(w, md.String())
+	return err
+}
+
+// scanChartString renders mean duration per parameter value as an ASCII bar chart
+func scanChartString(scan *ScanResult) string {
+	var bars []bar
+	for _, r := range scan.Results {
+		if r.Stats.N == 0 {
+			continue
+		}
+		bars = append(bars, bar{
+			Label: fmt.Sprintf("%s=%s", scan.Parameter, r.Config.Parameter.Value),
+			Value: r.Stats.Mean,
+		})
+	}
+
+	return barChartString(fmt.Sprintf("Mean Time vs %s", scan.Parameter), bars)
+}
+
+// comparisonSummary describes how much faster the fastest command was than each other command
+func comparisonSummary(comparison *Comparison) []string {
+	if comparison.Fastest < 0 {
+		return nil
+	}
+
+	fastest := comparison.Results[comparison.Fastest].Config.Label()
+	var lines []string
+	for i, r := range comparison.Results {
+		if i == comparison.Fastest || r.Stats.N == 0 {
+			continue
+		}
+		rel := comparison.Relative[i]
+		lines = append(lines, fmt.Sprintf("%s ran %.2f ± %.2f times faster than %s",
+			fastest, rel.Ratio, rel.Uncertainty, r.Config.Label()))
+	}
+	return lines
+}
+
+// formatRelative formats a relative speed as "1.23 ± 0.04"
+func formatRelative(rel RelativeSpeed) string {
+	if rel.Fastest {
+		return "1.00 (fastest)"
+	}
+	return fmt.Sprintf("%.2f ± %.2f", rel.Ratio, rel.Uncertainty)
+}
+
+// formatHookFailure formats a hook failure for console and Markdown output
+func formatHookFailure(f HookFailure) string {
+	switch f.Hook {
+	case HookPrepare, HookConclude:
+		if f.RunNumber == 0 {
+			return fmt.Sprintf("%s (warm-up): %s", f.Hook, f.Error)
+		}
+		return fmt.Sprintf("%s (run %d): %s", f.Hook, f.RunNumber, f.Error)
+	default:
+		return fmt.Sprintf("%s: %s", f.Hook, f.Error)
+	}
+}
+
+// formatShortDuration formats a duration in seconds compactly for use in tables
+func formatShortDuration(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond).String()
+}
+
+// formatBytes formats a byte count using binary units
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// resourceRecord formats resource usage as CSV columns
+func resourceRecord(usage ResourceUsage) []string {
+	return []string{
+		fmt.Sprintf("%.6f", usage.UserTime.Seconds()),
+		fmt.Sprintf("%.6f", usage.SystemTime.Seconds()),
+		fmt.Sprintf("%d", usage.MaxRSS),
+		fmt.Sprintf("%d", usage.VoluntaryCtxSwitches),
+		fmt.Sprintf("%d", usage.InvoluntaryCtxSwitches),
+		fmt.Sprintf("%d", usage.MinorPageFaults),
+		fmt.Sprintf("%d", usage.MajorPageFaults),
+	}
+}
+
+// formatEnvOverrides describes the environment customisation, e.g. "clean, GOFLAGS=-race".
+// Secret values are redacted. Returns an empty string when the environment is inherited as is.
+func formatEnvOverrides(config Config) string {
+	var parts []string
+	if config.CleanEnv {
+		parts = append(parts, "clean")
+	}
+	redacted := config.RedactedEnv()
+	for _, entry := range mergeEnv(nil, config.Env) {
+		name := envName(entry)
+		parts = append(parts, name+"="+redacted[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// saveFile creates filename and passes it to write, reporting errors from
+// both writing and closing the file
+func saveFile(filename string, write func(io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// PrintDiff outputs the comparison of a candidate benchmark against a baseline to the console
+func PrintDiff(diff Diff) {
+	fmt.Printf("\n")
+	fmt.Printf("Comparison\n")
+	fmt.Printf("==========\n\n")
+	fmt.Printf("Baseline:  %s\n", diff.Baseline.Name)
+	fmt.Printf("Candidate: %s\n\n", diff.Candidate.Name)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "\tBaseline\tCandidate\n")
+	fmt.Fprintf(w, "\t--------\t---------\n")
+	fmt.Fprintf(w, "N\t%d\t%d\n", diff.Baseline.N, diff.Candidate.N)
+	fmt.Fprintf(w, "Mean ± σ\t%s ± %s\t%s ± %s\n",
+		formatDiffDuration(diff.Baseline.Mean), formatDiffDuration(diff.Baseline.StdDev),
+		formatDiffDuration(diff.Candidate.Mean), formatDiffDuration(diff.Candidate.StdDev))
+	fmt.Fprintf(w, "Median\t%s\t%s\n", formatDiffDuration(diff.Baseline.Median), formatDiffDuration(diff.Candidate.Median))
+	w.Flush()
+	fmt.Printf("\n")
+
+	if diff.Verdict != VerdictInsufficient {
+		fmt.Printf("Difference:    %s (%s CI %s)\n", formatDifference(diff), FormatConfidence(diff.Confidence), formatDifferenceCI(diff))
+		fmt.Printf("Effect Size:   d = %.2f (%s)\n", diff.EffectSize, diff.EffectMagnitude())
+		fmt.Printf("Welch's t:     t = %.2f, df = %.1f, p %s\n", diff.WelchT, diff.WelchDF, FormatPValue(diff.WelchP))
+		if diff.MannWhitney {
+			fmt.Printf("Mann–Whitney:  U = %.1f, p %s\n", diff.MannWhitneyU, FormatPValue(diff.MannWhitneyP))
+		}
+	}
+	fmt.Printf("Verdict:       %s\n", diff.Summary())
+}
+
+// SaveDiffJSON saves the comparison of a candidate benchmark against a baseline as JSON
+func SaveDiffJSON(diff Diff, filename string) error {
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteDiffJSON(w, diff)
+	})
+}
+
+// WriteDiffJSON writes the comparison of a candidate benchmark against a baseline as JSON to w
+func WriteDiffJSON(w io.Writer, diff Diff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewDiffDocument(diff))
+}
+
+// SaveDiffMarkdown saves the comparison of a candidate benchmark against a baseline as a Markdown report
+func SaveDiffMarkdown(diff Diff, filename string) error {
+	return saveFile(filename, func(w io.Writer) error {
+		return WriteDiffMarkdown(w, diff)
+	})
+}
+
+// WriteDiffMarkdown writes the comparison of a candidate benchmark against a baseline as a Markdown report to w
+func WriteDiffMarkdown(w io.Writer, diff Diff) error {
+	var md strings.Builder
+
+	md.WriteString("# Caliper Comparison Report\n\n")
+	md.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format(time.RFC1123)))
+	md.WriteString(fmt.Sprintf("- **Baseline:** `%s`\n", diff.Baseline.Name))
+	md.WriteString(fmt.Sprintf("- **Candidate:** `%s`\n\n", diff.Candidate.Name))
+
+	md.WriteString("## Results\n\n")
+	md.WriteString("| | Baseline | Candidate |\n")
+	md.WriteString("|-|----------|-----------|\n")
+	md.WriteString(fmt.Sprintf("| N | %d | %d |\n", diff.Baseline.N, diff.Candidate.N))
+	md.WriteString(fmt.Sprintf("| Mean ± σ | %s ± %s | %s ± %s |\n",
+		formatDiffDuration(diff.Baseline.Mean), formatDiffDuration(diff.Baseline.StdDev),
+		formatDiffDuration(diff.Candidate.Mean), formatDiffDuration(diff.Candidate.StdDev)))
+	md.WriteString(fmt.Sprintf("| Median | %s | %s |\n\n", formatDiffDuration(diff.Baseline.Median), formatDiffDuration(diff.Candidate.Median)))
+
+	md.WriteString("## Verdict\n\n")
+	md.WriteString(fmt.Sprintf("**%s**\n\n", diff.Summary()))
+	if diff.Verdict != VerdictInsufficient {
+		md.WriteString(fmt.Sprintf("- **Difference:** %s (%s CI %s)\n", formatDifference(diff), FormatConfidence(diff.Confidence), formatDifferenceCI(diff)))
+		md.WriteString(fmt.Sprintf("- **Effect Size:** Cohen's d = %.2f (%s)\n", diff.EffectSize, diff.EffectMagnitude()))
+		md.WriteString(fmt.Sprintf("- **Welch's t-test:** t = %.2f, df = %.1f, p %s\n", diff.WelchT, diff.WelchDF, FormatPValue(diff.WelchP)))
+		if diff.MannWhitney {
+			md.WriteString(fmt.Sprintf("- **Mann–Whitney U test:** U = %.1f, p %s\n", diff.MannWhitneyU, FormatPValue(diff.MannWhitneyP)))
+		}
+		md.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, md.String())
+	return err
+}
+
+// FormatPValue formats a p-value with its relation, e.g. "= 0.042" or "< 0.001"
+func FormatPValue(p float64) string {
+	if p < 0.001 {
+		return "< 0.001"
+	}
+	return fmt.Sprintf("= %.3f", p)
+}
+
+// formatDifference formats the difference of the means, e.g. "-3.912s (-8.6%)"
+func formatDifference(diff Diff) string {
+	return fmt.Sprintf("%s (%+.1f%%)", formatSignedDuration(diff.Difference), diff.RelativeDifference*100)
+}
+
+// formatDifferenceCI formats the confidence interval of the difference, e.g. "-5.4s … -2.4s"
+func formatDifferenceCI(diff Diff) string {
+	return fmt.Sprintf("%s … %s", formatSignedDuration(diff.DifferenceCI.Low), formatSignedDuration(diff.DifferenceCI.High))
+}
+
+// formatSignedDuration formats a duration difference with an explicit sign
+func formatSignedDuration(seconds float64) string {
+	if seconds > 0 {
+		return "+" + formatDiffDuration(seconds)
+	}
+	return formatDiffDuration(seconds)
+}
+
+// formatDiffDuration formats a duration for a comparison, keeping sub-second
+// durations to 10µs so that small differences don't round away to "0s"
+func formatDiffDuration(seconds float64) string {
+	duration := time.Duration(seconds * float64(time.Second))
+	if duration > -time.Second && duration < time.Second {
+		return duration.Round(10 * time.Microsecond).String()
+	}
+	return duration.Round(time.Millisecond).String()
+}
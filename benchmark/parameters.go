@@ -0,0 +1,114 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parameter is a named value substituted into {name} placeholders
+type Parameter struct {
+	Name  string
+	Value string
+}
+
+// ScanResult holds the results of one command benchmarked across several parameter values
+type ScanResult struct {
+	Command   string    // Command template containing the {name} placeholder
+	Parameter string    // Parameter name
+	Results   []*Result // One result per parameter value, in scan order
+}
+
+// ScanValues returns the values from min to max (inclusive) in increments of step
+func ScanValues(min, max, step float64) ([]string, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be greater than 0")
+	}
+	if max < min {
+		return nil, fmt.Errorf("max (%g) must not be less than min (%g)", max, min)
+	}
+
+	// Compute the count up front to avoid accumulating floating point error
+	count := int(math.Floor((max-min)/step+1e-9)) + 1
+	values := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		values = append(values, strconv.FormatFloat(min+float64(i)*step, 'f', -1, 64))
+	}
+
+	return values, nil
+}
+
+// ParseScanRange parses a scan range written as "MIN..MAX", e.g. "-5..5"
+func ParseScanRange(value string) (min, max float64, err error) {
+	low, high, ok := strings.Cut(value, "..")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected MIN..MAX, got %q", value)
+	}
+	if min, err = strconv.ParseFloat(low, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid min %q: %w", low, err)
+	}
+	if max, err = strconv.ParseFloat(high, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid max %q: %w", high, err)
+	}
+	return min, max, nil
+}
+
+// ParseParameterList parses a comma-separated list of parameter values like "1,2,4,8"
+func ParseParameterList(list string) ([]string, error) {
+	if list == "" {
+		return nil, fmt.Errorf("parameter list cannot be empty")
+	}
+
+	parts := strings.Split(list, ",")
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("parameter list contains an empty value")
+		}
+		values = append(values, part)
+	}
+
+	return values, nil
+}
+
+// WithParameter returns a copy of the config with {name} placeholders replaced
+// in the command, command name, hooks, environment values, working directory,
+// service and readiness checks, and the parameter recorded
+func (c Config) WithParameter(param Parameter) Config {
+	placeholder := "{" + param.Name + "}"
+	for _, field := range c.parameterFields() {
+		*field = strings.ReplaceAll(*field, placeholder, param.Value)
+	}
+	c.Parameter = param
+	return c
+}
+
+// UsesParameter reports whether any field expanded by WithParameter contains
+// the {name} placeholder
+func (c Config) UsesParameter(name string) bool {
+	for _, field := range c.parameterFields() {
+		if strings.Contains(*field, "{"+name+"}") {
+			return true
+		}
+	}
+	return false
+}
+
+// parameterFields returns the fields in which parameter placeholders are
+// expanded. The environment is copied first, so that expanding the fields
+// leaves the config they came from as it was.
+func (c *Config) parameterFields() []*string {
+	c.Env = append([]string(nil), c.Env...)
+	fields := []*string{
+		&c.Command, &c.CommandName,
+		&c.Setup, &c.Prepare, &c.Conclude, &c.Cleanup,
+		&c.Dir,
+		&c.Service, &c.Readiness.TCP, &c.Readiness.HTTP, &c.Readiness.Output,
+	}
+	for i := range c.Env {
+		fields = append(fields, &c.Env[i])
+	}
+	return fields
+}
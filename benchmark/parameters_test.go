@@ -0,0 +1,90 @@
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithParameter(t *testing.T) {
+	config := Config{
+		Command: "curl localhost:{P}",
+		Setup:   "init {P}",
+		Dir:     "port-{P}",
+		Env:     []string{"PORT={P}"},
+		Service: "srv --port {P}",
+		Readiness: ReadinessProbe{
+			TCP:    "localhost:{P}",
+			HTTP:   "http://localhost:{P}/health",
+			Output: "listening on {P}",
+		},
+	}
+
+	variant := config.WithParameter(Parameter{Name: "P", Value: "8080"})
+	want := Config{
+		Command: "curl localhost:8080",
+		Setup:   "init 8080",
+		Dir:     "port-8080",
+		Env:     []string{"PORT=8080"},
+		Service: "srv --port 8080",
+		Readiness: ReadinessProbe{
+			TCP:    "localhost:8080",
+			HTTP:   "http://localhost:8080/health",
+			Output: "listening on 8080",
+		},
+		Parameter: Parameter{Name: "P", Value: "8080"},
+	}
+	if !reflect.DeepEqual(variant, want) {
+		t.Errorf("WithParameter = %+v, want %+v", variant, want)
+	}
+	if config.Env[0] != "PORT={P}" || config.Service != "srv --port {P}" {
+		t.Errorf("WithParameter changed the template: %+v", config)
+	}
+	if err := variant.Readiness.Validate(); err != nil {
+		t.Errorf("expanded readiness probe: %v", err)
+	}
+}
+
+func TestUsesParameter(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   bool
+	}{
+		{"command", Config{Command: "sleep {P}"}, true},
+		{"service only", Config{Command: "true", Service: "srv --port {P}"}, true},
+		{"readiness only", Config{Command: "true", Readiness: ReadinessProbe{TCP: "localhost:{P}"}}, true},
+		{"environment only", Config{Command: "true", Env: []string{"N={P}"}}, true},
+		{"other placeholder", Config{Command: "sleep {Q}"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.config.UsesParameter("P"); got != tt.want {
+			t.Errorf("%s: UsesParameter = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseScanRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		min, max float64
+		err      bool
+	}{
+		{"1..16", 1, 16, false},
+		{"-5..5", -5, 5, false},
+		{"-10..-2.5", -10, -2.5, false},
+		{"0.5..1.5", 0.5, 1.5, false},
+		{"1-16", 0, 0, true},
+		{"..5", 0, 0, true},
+		{"a..b", 0, 0, true},
+	}
+	for _, tt := range tests {
+		min, max, err := ParseScanRange(tt.value)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseScanRange(%q) error = %v, want error %t", tt.value, err, tt.err)
+			continue
+		}
+		if min != tt.min || max != tt.max {
+			t.Errorf("ParseScanRange(%q) = %g, %g, want %g, %g", tt.value, min, max, tt.min, tt.max)
+		}
+	}
+}
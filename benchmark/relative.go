@@ -0,0 +1,66 @@
+package benchmark
+
+import "math"
+
+// RelativeSpeed describes a command's mean duration relative to the fastest command
+type RelativeSpeed struct {
+	Ratio       float64 // Mean / fastest mean (1.0 = fastest, 0 = no successful runs)
+	Uncertainty float64 // Standard deviation of the ratio, propagated from both std devs
+	Fastest     bool    // True for the command with the lowest mean
+}
+
+// Comparison holds the results of several commands benchmarked in one invocation
+type Comparison struct {
+	Results  []*Result
+	Relative []RelativeSpeed // Same order as Results
+	Fastest  int             // Index of the fastest command in Results (-1 if none succeeded)
+}
+
+// Compare computes the speed of each result relative to the fastest one
+func Compare(results []*Result) *Comparison {
+	comparison := &Comparison{
+		Results:  results,
+		Relative: make([]RelativeSpeed, len(results)),
+		Fastest:  -1,
+	}
+
+	// Find the fastest command among those with successful runs
+	for i, r := range results {
+		if r.Stats.N == 0 {
+			continue
+		}
+		if comparison.Fastest < 0 || r.Stats.Mean < results[comparison.Fastest].Stats.Mean {
+			comparison.Fastest = i
+		}
+	}
+
+	if comparison.Fastest < 0 {
+		return comparison
+	}
+
+	fastest := results[comparison.Fastest].Stats
+	for i, r := range results {
+		if r.Stats.N == 0 {
+			continue
+		}
+
+		if i == comparison.Fastest {
+			comparison.Relative[i] = RelativeSpeed{Ratio: 1.0, Fastest: true}
+			continue
+		}
+
+		// Gaussian error propagation for a quotient: σr/r = sqrt((σa/a)² + (σb/b)²)
+		ratio := r.Stats.Mean / fastest.Mean
+		uncertainty := ratio * math.Sqrt(
+			math.Pow(r.Stats.StdDev/r.Stats.Mean, 2)+
+				math.Pow(fastest.StdDev/fastest.Mean, 2),
+		)
+
+		comparison.Relative[i] = RelativeSpeed{
+			Ratio:       ratio,
+			Uncertainty: uncertainty,
+		}
+	}
+
+	return comparison
+}
@@ -0,0 +1,297 @@
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Reporter receives progress notifications while a benchmark runs.
+// Methods are called synchronously from Run, in order, and must not block.
+// Embed NopReporter to implement only the events you care about.
+type Reporter interface {
+	// BenchmarkStarted is called once, before calibration and the setup hook
+	BenchmarkStarted(config Config)
+	// Resumed is called after BenchmarkStarted when runs were loaded from a checkpoint
+	Resumed(session int, runs []RunResult)
+	// CalibrationFinished is called after the shell overhead was measured (or failed to be)
+	CalibrationFinished(shell string, overhead time.Duration, err error)
+	// HookStarted and HookFinished bracket the setup and cleanup hooks.
+	// Prepare and conclude hooks are part of a run and reported through RunFinished.
+	HookStarted(hook string)
+	HookFinished(hook string, err error)
+	// ServiceStarted and ServiceReady bracket the start of the service until its
+	// readiness probe passed; ServiceStopped follows the runs (err is set when
+	// the service exited on its own)
+	ServiceStarted(command string)
+	ServiceReady(after time.Duration, err error)
+	ServiceStopped(err error)
+	// RunStarted is called before the prepare hook of a warm-up or measured run
+	RunStarted(event RunEvent)
+	// RunFinished is called after the conclude hook, with the failures of the run's hooks
+	RunFinished(event RunEvent, run RunResult, hookFailures []HookFailure)
+	// HostSampled is called with each sample of the host's background load:
+	// before the first run, after each run and while waiting for a quiet host
+	HostSampled(sample HostSample)
+	// WaitingForQuietHost is called when Config.RequireQuietHost delays a run
+	// because of a noisy sample
+	WaitingForQuietHost(sample HostSample)
+	// WarmupFinished is called once all warm-up runs succeeded (not when warm-up is skipped)
+	WarmupFinished(result *Result)
+	// BenchmarkFinished is called with the final result, including statistics
+	BenchmarkFinished(result *Result)
+}
+
+// RunEvent identifies a run in progress notifications
+type RunEvent struct {
+	Warmup bool   // Warm-up run, excluded from the statistics
+	Number int    // 1-based number among the warm-up or the measured runs
+	Label  string // Progress label such as "Run 3/10" or "Warm-up 2 (auto, max 10)"
+}
+
+// NopReporter ignores every notification. It is the default reporter of Run.
+type NopReporter struct{}
+
+func (NopReporter) BenchmarkStarted(Config)                          {}
+func (NopReporter) Resumed(int, []RunResult)                         {}
+func (NopReporter) CalibrationFinished(string, time.Duration, error) {}
+func (NopReporter) HookStarted(string)                               {}
+func (NopReporter) HookFinished(string, error)                       {}
+func (NopReporter) ServiceStarted(string)                            {}
+func (NopReporter) ServiceReady(time.Duration, error)                {}
+func (NopReporter) ServiceStopped(error)                             {}
+func (NopReporter) RunStarted(RunEvent)                              {}
+func (NopReporter) RunFinished(RunEvent, RunResult, []HookFailure)   {}
+func (NopReporter) HostSampled(HostSample)                           {}
+func (NopReporter) WaitingForQuietHost(HostSample)                   {}
+func (NopReporter) WarmupFinished(*Result)                           {}
+func (NopReporter) BenchmarkFinished(*Result)                        {}
+
+// Option configures Run
+type Option func(*runOptions)
+
+// runOptions holds the settings applied by Options
+type runOptions struct {
+	reporter Reporter
+}
+
+// WithReporter sends progress notifications to r (default: none)
+func WithReporter(r Reporter) Option {
+	return func(o *runOptions) {
+		o.reporter = r
+	}
+}
+
+// ConsoleReporter prints the progress of a benchmark the way the caliper CLI does
+type ConsoleReporter struct {
+	w          io.Writer
+	config     Config
+	warmupRuns []RunResult   // Completed warm-up runs, for the change between consecutive runs
+	runs       []RunResult   // Completed measured runs, for the confidence interval progress
+	overhead   time.Duration // Calibrated shell overhead, subtracted from the runs
+	waiting    bool          // Waiting for a quiet host
+}
+
+// NewConsoleReporter returns a reporter writing progress lines to w
+func NewConsoleReporter(w io.Writer) *ConsoleReporter {
+	return &ConsoleReporter{w: w}
+}
+
+// BenchmarkStarted implements Reporter
+func (r *ConsoleReporter) BenchmarkStarted(config Config) {
+	r.config = config
+	r.warmupRuns = nil
+	r.runs = nil
+	r.overhead = 0
+	fmt.Fprintf(r.w, "Starting benchmark...\n\n")
+}
+
+// Resumed implements Reporter
+func (r *ConsoleReporter) Resumed(session int, runs []RunResult) {
+	for _, run := range runs {
+		if run.Success {
+			r.runs = append(r.runs, run)
+		}
+	}
+	fmt.Fprintf(r.w, "Resuming from checkpoint: %d runs completed in earlier sessions, starting session %d\n\n", len(runs), session)
+}
+
+// CalibrationFinished implements Reporter
+func (r *ConsoleReporter) CalibrationFinished(shell string, overhead time.Duration, err error) {
+	fmt.Fprintf(r.w, "Calibrating %s overhead: ", shell)
+	if err != nil {
+		fmt.Fprintf(r.w, "✗ Failed: %v\n", err)
+		return
+	}
+	r.overhead = overhead
+	fmt.Fprintf(r.w, "%v (median of %d empty runs, subtracted from statistics)\n\n", overhead, calibrationRuns)
+}
+
+// HookStarted implements Reporter
+func (r *ConsoleReporter) HookStarted(hook string) {
+	switch hook {
+	case HookSetup:
+		fmt.Fprintf(r.w, "Setup: ")
+	case HookCleanup:
+		fmt.Fprintf(r.w, "\nCleanup: ")
+	}
+	if r.config.Debug {
+		fmt.Fprintf(r.w, "(streaming output)\n")
+	}
+}
+
+// HookFinished implements Reporter
+func (r *ConsoleReporter) HookFinished(hook string, err error) {
+	if err != nil {
+		fmt.Fprintf(r.w, "✗ Failed: %v\n", err)
+		return
+	}
+	if hook == HookSetup {
+		fmt.Fprintf(r.w, "✓ Completed\n\n")
+	} else {
+		fmt.Fprintf(r.w, "✓ Completed\n")
+	}
+}
+
+// ServiceStarted implements Reporter
+func (r *ConsoleReporter) ServiceStarted(command string) {
+	fmt.Fprintf(r.w, "Service: ")
+	if r.config.Debug {
+		fmt.Fprintf(r.w, "(streaming output)\n")
+	}
+}
+
+// ServiceReady implements Reporter
+func (r *ConsoleReporter) ServiceReady(after time.Duration, err error) {
+	if err != nil {
+		fmt.Fprintf(r.w, "✗ Failed: %v\n", err)
+		return
+	}
+	if !r.config.Readiness.Configured() {
+		fmt.Fprintf(r.w, "✓ Started (no readiness probe)\n\n")
+		return
+	}
+	fmt.Fprintf(r.w, "✓ Ready after %v (%s)\n\n", after.Round(time.Millisecond), r.config.Readiness.Description())
+}
+
+// ServiceStopped implements Reporter
+func (r *ConsoleReporter) ServiceStopped(err error) {
+	if err != nil {
+		fmt.Fprintf(r.w, "\n⚠ Service %v\n", err)
+	}
+}
+
+// RunStarted implements Reporter
+func (r *ConsoleReporter) RunStarted(event RunEvent) {
+	if r.config.Debug {
+		fmt.Fprintf(r.w, "%s: (streaming output)\n", event.Label)
+	} else {
+		fmt.Fprintf(r.w, "%s: ", event.Label)
+	}
+}
+
+// RunFinished implements Reporter
+func (r *ConsoleReporter) RunFinished(event RunEvent, run RunResult, hookFailures []HookFailure) {
+	// In debug mode the command's output sits between the label and the outcome
+	prefix := ""
+	if r.config.Debug {
+		prefix = event.Label + ": "
+	}
+
+	switch {
+	case run.Status == RunStatusInterrupted && event.Warmup:
+		fmt.Fprintf(r.w, "%s✗ Interrupted\n", prefix)
+		return
+	case run.Status == RunStatusInterrupted:
+		fmt.Fprintf(r.w, "%s✗ Interrupted (run discarded)\n", prefix)
+		return
+	case !run.Success && run.Retried():
+		fmt.Fprintf(r.w, "%s✗ Failed after %d attempts: %s\n", prefix, run.Attempt, run.Error)
+		if !r.config.Debug {
+			r.printStderrTail(run)
+		}
+	case !run.Success:
+		fmt.Fprintf(r.w, "%s✗ Failed: %s\n", prefix, run.Error)
+		if !r.config.Debug {
+			r.printStderrTail(run)
+		}
+	case event.Warmup:
+		r.warmupRuns = append(r.warmupRuns, run)
+		fmt.Fprintf(r.w, "%s✓ Completed in %v (excluded from stats)%s%s\n", prefix, run.Duration, warmupChange(r.config, r.warmupRuns), retryNote(run))
+	default:
+		r.runs = append(r.runs, run)
+		fmt.Fprintf(r.w, "%s✓ Completed in %v%s%s\n", prefix, run.Duration, ciProgress(r.config, r.runs, r.overhead), retryNote(run))
+	}
+
+	for _, f := range hookFailures {
+		if f.Hook == HookConclude {
+			fmt.Fprintf(r.w, "  ⚠ Conclude hook failed: %s\n", f.Error)
+		}
+	}
+}
+
+// HostSampled implements Reporter
+func (r *ConsoleReporter) HostSampled(sample HostSample) {
+	switch {
+	case r.waiting && !sample.Noisy():
+		r.waiting = false
+		fmt.Fprintf(r.w, "✓ Host is quiet\n")
+	case r.waiting:
+		r.waiting = false
+		fmt.Fprintf(r.w, "✗ Host is still busy: %s\n", sample.Description())
+	case sample.Noisy() && sample.Run == 0:
+		fmt.Fprintf(r.w, "⚠ Host is busy before the first run: %s\n\n", sample.Description())
+	case sample.Noisy():
+		fmt.Fprintf(r.w, "  ⚠ Background load during the run: %s\n", sample.Description())
+	}
+}
+
+// WaitingForQuietHost implements Reporter
+func (r *ConsoleReporter) WaitingForQuietHost(sample HostSample) {
+	r.waiting = true
+	fmt.Fprintf(r.w, "Waiting up to %s for a quiet host (%s)... ", quietHostTimeout, sample.Description())
+}
+
+// WarmupFinished implements Reporter
+func (r *ConsoleReporter) WarmupFinished(result *Result) {
+	if result.Config.WarmupAuto && !result.WarmupStable {
+		fmt.Fprintf(r.w, "⚠ Warm-up durations did not stabilise within %.0f%% after %d runs\n", result.Config.WarmupTolerance*100, len(result.WarmupRuns))
+	}
+	fmt.Fprintln(r.w)
+}
+
+// BenchmarkFinished implements Reporter
+func (r *ConsoleReporter) BenchmarkFinished(result *Result) {
+	if result.Interrupted {
+		fmt.Fprintf(r.w, "\nInterrupted after %d completed runs, reporting those only\n", len(result.Runs))
+	} else if result.Config.Adaptive() {
+		fmt.Fprintf(r.w, "\nStopped after %d runs: %s\n", len(result.Runs), result.StopReason.Description())
+	}
+}
+
+// printStderrTail shows the end of a failed run's stderr below its progress line
+func (r *ConsoleReporter) printStderrTail(run RunResult) {
+	if run.StderrTail == "" {
+		return
+	}
+	for _, line := range strings.Split(run.StderrTail, "\n") {
+		fmt.Fprintf(r.w, "    │ %s\n", line)
+	}
+	if run.StderrLog != "" {
+		fmt.Fprintf(r.w, "    └ full output: %s\n", run.StderrLog)
+	}
+}
+
+// retryNote describes the failed attempts before a successful retry, e.g.
+// " (attempt 2, retried after: exit status 101)"
+func retryNote(run RunResult) string {
+	if !run.Retried() {
+		return ""
+	}
+	errs := make([]string, len(run.FailedAttempts))
+	for i, attempt := range run.FailedAttempts {
+		errs[i] = attempt.Error
+	}
+	return fmt.Sprintf(" (attempt %d, retried after: %s)", run.Attempt, strings.Join(errs, "; "))
+}
@@ -0,0 +1,112 @@
+package benchmark
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// ResourceUsage holds the OS-reported resource usage of a single run.
+// Values cover the whole process tree that was waited for by the shell.
+type ResourceUsage struct {
+	UserTime               time.Duration // CPU time spent in user mode
+	SystemTime             time.Duration // CPU time spent in kernel mode
+	MaxRSS                 int64         // Peak resident set size in bytes (largest process in the tree)
+	VoluntaryCtxSwitches   int64         // Context switches due to blocking (I/O, locks)
+	InvoluntaryCtxSwitches int64         // Context switches due to preemption
+	MinorPageFaults        int64         // Page faults serviced without I/O
+	MajorPageFaults        int64         // Page faults that required I/O
+}
+
+// CPUTime returns the total CPU time (user + system)
+func (r ResourceUsage) CPUTime() time.Duration {
+	return r.UserTime + r.SystemTime
+}
+
+// ResourceStatistics holds resource usage aggregated over successful runs
+type ResourceStatistics struct {
+	MeanUserTime               float64 // Mean user CPU time in seconds
+	MeanSystemTime             float64 // Mean system CPU time in seconds
+	MeanCPUTime                float64 // Mean total CPU time in seconds
+	CPUUtilization             float64 // Mean CPU time / mean wall time (1.0 = one core fully busy)
+	MeanMaxRSS                 float64 // Mean peak RSS in bytes
+	PeakMaxRSS                 int64   // Highest peak RSS across all runs in bytes
+	MeanVoluntaryCtxSwitches   float64 // Mean voluntary context switches
+	MeanInvoluntaryCtxSwitches float64 // Mean involuntary context switches
+	MeanMinorPageFaults        float64 // Mean minor page faults
+	MeanMajorPageFaults        float64 // Mean major page faults
+}
+
+// resourceUsage extracts resource usage from a finished process
+func resourceUsage(state *os.ProcessState) ResourceUsage {
+	usage := ResourceUsage{
+		UserTime:   state.UserTime(),
+		SystemTime: state.SystemTime(),
+	}
+
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || rusage == nil {
+		return usage
+	}
+
+	// Linux reports ru_maxrss in kilobytes, macOS in bytes
+	usage.MaxRSS = int64(rusage.Maxrss)
+	if runtime.GOOS != "darwin" {
+		usage.MaxRSS *= 1024
+	}
+	usage.VoluntaryCtxSwitches = int64(rusage.Nvcsw)
+	usage.InvoluntaryCtxSwitches = int64(rusage.Nivcsw)
+	usage.MinorPageFaults = int64(rusage.Minflt)
+	usage.MajorPageFaults = int64(rusage.Majflt)
+
+	return usage
+}
+
+// CalculateResourceStatistics aggregates resource usage over the successful runs
+func CalculateResourceStatistics(runs []RunResult) ResourceStatistics {
+	stats := ResourceStatistics{}
+
+	n := 0
+	wallTime := 0.0
+	for _, run := range runs {
+		if !run.Success {
+			continue
+		}
+		n++
+		wallTime += run.Duration.Seconds()
+
+		usage := run.Resources
+		stats.MeanUserTime += usage.UserTime.Seconds()
+		stats.MeanSystemTime += usage.SystemTime.Seconds()
+		stats.MeanMaxRSS += float64(usage.MaxRSS)
+		stats.MeanVoluntaryCtxSwitches += float64(usage.VoluntaryCtxSwitches)
+		stats.MeanInvoluntaryCtxSwitches += float64(usage.InvoluntaryCtxSwitches)
+		stats.MeanMinorPageFaults += float64(usage.MinorPageFaults)
+		stats.MeanMajorPageFaults += float64(usage.MajorPageFaults)
+
+		if usage.MaxRSS > stats.PeakMaxRSS {
+			stats.PeakMaxRSS = usage.MaxRSS
+		}
+	}
+
+	if n == 0 {
+		return stats
+	}
+
+	count := float64(n)
+	stats.MeanUserTime /= count
+	stats.MeanSystemTime /= count
+	stats.MeanCPUTime = stats.MeanUserTime + stats.MeanSystemTime
+	stats.MeanMaxRSS /= count
+	stats.MeanVoluntaryCtxSwitches /= count
+	stats.MeanInvoluntaryCtxSwitches /= count
+	stats.MeanMinorPageFaults /= count
+	stats.MeanMajorPageFaults /= count
+
+	if wallTime > 0 {
+		stats.CPUUtilization = stats.MeanCPUTime / (wallTime / count)
+	}
+
+	return stats
+}
@@ -0,0 +1,85 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+)
+
+// executeRunWithRetries runs one iteration and retries it while it fails and
+// retries are left. Only the final attempt is timed for the statistics; the
+// earlier attempts are kept in FailedAttempts, each with its own log files
+// (logName-attempt-N), and their hook failures are not reported.
+func executeRunWithRetries(ctx context.Context, runNumber int, logName string, config Config) (RunResult, []HookFailure) {
+	var failed []RunResult
+	for attempt := 1; ; attempt++ {
+		name := logName
+		if attempt > 1 {
+			name = fmt.Sprintf("%s-attempt-%d", logName, attempt)
+		}
+
+		result, hookFailures := executeRun(ctx, runNumber, name, config)
+		result.Attempt = attempt
+		if result.Success || attempt > config.Retries || ctx.Err() != nil || !config.retryable(result) {
+			result.FailedAttempts = failed
+			return result, hookFailures
+		}
+		failed = append(failed, result)
+	}
+}
+
+// retryable reports whether a failed attempt may be retried. Without
+// RetryOnExitCodes every failure is retried; otherwise only runs that exited
+// with one of those codes are.
+func (c Config) retryable(result RunResult) bool {
+	if len(c.RetryOnExitCodes) == 0 {
+		return true
+	}
+	if result.Status != RunStatusFailed {
+		return false
+	}
+	for _, code := range c.RetryOnExitCodes {
+		if result.ExitCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Retried reports whether the run needed more than one attempt
+func (r RunResult) Retried() bool {
+	return len(r.FailedAttempts) > 0
+}
+
+// RetriedRuns returns the measured runs that needed more than one attempt
+func (r *Result) RetriedRuns() []RunResult {
+	var retried []RunResult
+	for _, run := range r.Runs {
+		if run.Retried() {
+			retried = append(retried, run)
+		}
+	}
+	return retried
+}
+
+// Retries returns the total number of retried attempts across the measured runs
+func (r *Result) Retries() int {
+	count := 0
+	for _, run := range r.Runs {
+		count += len(run.FailedAttempts)
+	}
+	return count
+}
+
+// flakiness returns the percentage of runs that only succeeded after a retry
+func flakiness(runs []RunResult) float64 {
+	if len(runs) == 0 {
+		return 0
+	}
+	flaky := 0
+	for _, run := range runs {
+		if run.Success && run.Retried() {
+			flaky++
+		}
+	}
+	return float64(flaky) / float64(len(runs)) * 100
+}
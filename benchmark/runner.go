@@ -1,152 +1,619 @@
 package benchmark
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
+	"sync"
+	"syscall"
 	"time"
 )
 
+// killWaitDelay bounds how long we wait for output pipes to close after a run was killed
+const killWaitDelay = 5 * time.Second
+
+// interruptGracePeriod is how long an interrupted run may take to exit after
+// SIGINT before its process group is killed
+const interruptGracePeriod = 2 * time.Second
+
 // Config holds the benchmark configuration
 type Config struct {
-	Command    string
-	Runs       int
-	Name       string
-	OutputDir  string
-	SkipWarmup bool
-	Debug      bool // Enable verbose output (stream command stdout/stderr)
+	Command           string
+	CommandName       string    // Display name for the command (default: the command itself)
+	Parameter         Parameter // Parameter substituted into the command (empty when not scanning)
+	Runs              int
+	Name              string
+	OutputDir         string
+	SkipWarmup        bool
+	Warmup            int            // Number of warm-up runs (default 1, ignored with WarmupAuto)
+	WarmupAuto        bool           // Keep warming up until consecutive durations are within WarmupTolerance
+	WarmupTolerance   float64        // Relative difference between consecutive warm-up runs considered stable
+	MaxWarmupRuns     int            // Upper bound on warm-up runs in auto mode
+	Debug             bool           // Enable verbose output (stream command stdout/stderr)
+	Shell             string         // Shell used to run the command: sh, bash, zsh, a path, or "none" (default: bash)
+	Calibrate         bool           // Measure the shell's spawn overhead and subtract it from the statistics
+	Timeout           time.Duration  // Per-run timeout (0 = no timeout)
+	Env               []string       // KEY=VALUE variables set for the command and hooks
+	CleanEnv          bool           // Start from a minimal allow-listed environment instead of inheriting it
+	Dir               string         // Working directory of the command and hooks (default: current directory)
+	Setup             string         // Hook run once before all runs
+	Prepare           string         // Hook run before every run (untimed)
+	Conclude          string         // Hook run after every run (untimed)
+	Cleanup           string         // Hook run once after all runs
+	Service           string         // Command started before the runs and stopped after them, e.g. a server the command talks to
+	Readiness         ReadinessProbe // When the service (or the command with MeasureStartup) is ready
+	MeasureStartup    bool           // Time the command from its start until Readiness passes, then stop it
+	ExpectedExitCodes []int          // Exit codes that count as success (default: 0 only)
+	IgnoreFailure     bool           // Count every completed run as successful, whatever its exit code
+	Retries           int            // Extra attempts for a failed run before it counts as failed
+	RetryOnExitCodes  []int          // Only retry runs that exited with one of these codes (default: any failure)
+	LogOutput         bool           // Capture each run's stdout/stderr to log files in LogDir()
+	LogSizeLimit      int64          // Maximum bytes kept per log file (default 10 MiB)
+	StderrTailLines   int            // Lines of stderr kept in RunResult.StderrTail (default 10)
+	OutlierMethod     OutlierMethod  // How outlier runs are detected (default: IQR)
+	TrimOutliers      bool           // Compute the statistics without the outlier runs
+	Confidence        float64        // Confidence level of the reported intervals and of TargetCI (default 0.95)
+	Percentiles       []float64      // Percentiles reported in addition to P90 and P95, e.g. 50, 75, 99
+	Checkpoint        bool           // Append each completed run to CheckpointPath()
+	RequireQuietHost  bool           // Wait before each run until the host's background load is low, abort after 5 minutes
+	Resume            bool           // Continue from the runs in CheckpointPath() instead of starting over
+
+	// Adaptive run count (enabled when TargetCI > 0, Runs is then ignored)
+	TargetCI    float64       // Stop once the relative CI half-width is at or below this (0.02 = ±2%)
+	CIStatistic CIStatistic   // Statistic whose confidence interval is targeted (mean or median)
+	MinRuns     int           // Minimum number of measured runs before checking the target
+	MaxRuns     int           // Maximum number of measured runs
+	MaxTime     time.Duration // Time budget for the measured runs (0 = no budget)
 }
 
+// Label returns the display name of the benchmarked command
+func (c Config) Label() string {
+	if c.CommandName != "" {
+		return c.CommandName
+	}
+	return c.Command
+}
+
+// RunStatus describes the outcome of a single benchmark run
+type RunStatus string
+
+const (
+	RunStatusSuccess     RunStatus = "success"
+	RunStatusFailed      RunStatus = "failed"
+	RunStatusTimedOut    RunStatus = "timed-out"
+	RunStatusHookFailed  RunStatus = "hook-failed" // Prepare hook failed, command was not run
+	RunStatusInterrupted RunStatus = "interrupted" // Benchmark was interrupted while the run was in progress
+)
+
 // RunResult holds the result of a single benchmark run
 type RunResult struct {
-	RunNumber int
-	Duration  time.Duration
-	Success   bool
-	Error     string
+	RunNumber  int
+	Session    int // Session the run was measured in (see Result.Sessions)
+	Duration   time.Duration
+	Success    bool
+	Status     RunStatus
+	Error      string
+	ExitCode   int           // Exit code of the command (-1 if killed by a signal or not started)
+	StderrTail string        // Last lines of stderr, for diagnosing failures
+	StdoutLog  string        // Path of the stdout log file (empty unless LogOutput is set)
+	StderrLog  string        // Path of the stderr log file (empty unless LogOutput is set)
+	Resources  ResourceUsage // CPU time, peak RSS, context switches and page faults
+	Outlier    bool          // Duration lies outside the outlier fences of the successful runs
+
+	Attempt        int         // Attempt that produced this result (1 unless retried)
+	FailedAttempts []RunResult // Earlier attempts of a retried run, oldest first
 }
 
 // Result holds the complete benchmark results
 type Result struct {
 	Config        Config
-	WarmupRun     *RunResult
+	WarmupRuns    []RunResult
+	WarmupStable  bool // Auto warm-up reached the tolerance (false if it hit MaxWarmupRuns)
+	ColdFirstRun  bool // First measured run is a slow outlier, the warm-up may have been too short
 	Runs          []RunResult
+	HookFailures  []HookFailure
 	Stats         Statistics
 	SuccessRate   float64
+	Flakiness     float64           // Percentage of runs that only succeeded after a retry
+	StopReason    StopReason        // Why the measurement loop ended
+	ShellOverhead time.Duration     // Measured shell spawn time subtracted from the statistics (0 unless calibrated)
+	RelativeCI    float64           // Relative CI half-width of the targeted statistic (0 with fewer than 2 successful runs)
+	Environment   map[string]string // Effective environment of the command, secret values redacted
+	Interrupted   bool              // Cancelled before completion; Runs and Stats cover the completed runs only
+	Sessions      []Session         // Invocations that contributed runs; more than one when resumed from a checkpoint
+	ServiceReady  time.Duration     // Time the service took to pass its readiness probe (0 without a service)
+	HostSamples   []HostSample      // Background load before the first run and during each run (empty without /proc)
 	StartTime     time.Time
 	EndTime       time.Time
 	TotalDuration time.Duration
 }
 
-// Run executes the benchmark according to the provided configuration
-func Run(config Config) (*Result, error) {
+// MeetsSuccessRate reports whether the benchmark passes a failure tolerance policy:
+// at least minSuccessRate percent of the runs succeeded and no hook failed
+func (r *Result) MeetsSuccessRate(minSuccessRate float64) bool {
+	return r.SuccessRate >= minSuccessRate && len(r.HookFailures) == 0
+}
+
+// SuccessfulRuns returns the number of successful measured runs. It differs
+// from Stats.N when outliers were trimmed from the statistics.
+func (r *Result) SuccessfulRuns() int {
+	count := 0
+	for _, run := range r.Runs {
+		if run.Success {
+			count++
+		}
+	}
+	return count
+}
+
+// Durations returns the durations in seconds the statistics were computed
+// from: the successful runs, without the shell overhead when calibrated and
+// without the outliers when they were trimmed
+func (r *Result) Durations() []float64 {
+	return measuredDurations(r.Runs, r.ShellOverhead, r.Stats.Trimmed)
+}
+
+// measuredDurations returns the durations of the successful runs minus the
+// shell overhead, leaving out the runs flagged as outliers when trimmed
+func measuredDurations(runs []RunResult, overhead time.Duration, trimmed bool) []float64 {
+	if trimmed {
+		kept := make([]RunResult, 0, len(runs))
+		for _, run := range runs {
+			if !run.Outlier {
+				kept = append(kept, run)
+			}
+		}
+		runs = kept
+	}
+	return subtractOverhead(successfulDurations(runs), overhead)
+}
+
+// TimedOutRuns returns the number of measured runs that exceeded the timeout
+func (r *Result) TimedOutRuns() int {
+	count := 0
+	for _, run := range r.Runs {
+		if run.Status == RunStatusTimedOut {
+			count++
+		}
+	}
+	return count
+}
+
+// Run executes the benchmark according to the provided configuration.
+// Cancelling ctx forwards SIGINT to the running command, discards the run in
+// progress and returns the statistics of the completed runs with Interrupted set.
+// Run prints nothing unless a reporter is given with WithReporter, or
+// Config.Debug streams the command's output.
+func Run(ctx context.Context, config Config, opts ...Option) (*Result, error) {
+	options := runOptions{reporter: NopReporter{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	reporter := options.reporter
+
+	config = config.withAdaptiveDefaults().withWarmupDefaults().withLogDefaults().withServiceDefaults()
 	result := &Result{
-		Config:    config,
-		Runs:      make([]RunResult, 0, config.Runs),
-		StartTime: time.Now(),
+		Config:      config,
+		Runs:        make([]RunResult, 0, config.Runs),
+		Environment: config.RecordedEnvironment(),
+		StartTime:   time.Now(),
 	}
+	result.Sessions = []Session{{Number: 1, StartTime: result.StartTime}}
 
-	fmt.Printf("Starting benchmark...\n\n")
+	if err := config.ValidateShell(); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateEnvironment(); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateService(); err != nil {
+		return nil, err
+	}
+	if err := ValidatePercentiles(config.Percentiles); err != nil {
+		return nil, err
+	}
 
-	// Execute warm-up run if enabled
-	if !config.SkipWarmup {
-		if config.Debug {
-			fmt.Printf("Warm-up: (streaming output)\n")
-		} else {
-			fmt.Printf("Warm-up: ")
+	// Load completed runs from an earlier session before anything else runs
+	var cp *checkpoint
+	if config.Checkpoint || config.Resume {
+		var err error
+		if cp, err = openCheckpoint(config, result); err != nil {
+			return nil, err
 		}
-		warmupResult := executeCommand(0, config.Command, config.Debug)
-		result.WarmupRun = &warmupResult
+		defer cp.close()
+	}
+	session := &result.Sessions[len(result.Sessions)-1]
 
-		if warmupResult.Success {
-			if config.Debug {
-				fmt.Printf("Warm-up: ✓ Completed in %v (excluded from stats)\n", warmupResult.Duration)
-			} else {
-				fmt.Printf("✓ Completed in %v (excluded from stats)\n", warmupResult.Duration)
-			}
-		} else {
-			if config.Debug {
-				fmt.Printf("Warm-up: ✗ Failed: %s\n", warmupResult.Error)
-			} else {
-				fmt.Printf("✗ Failed: %s\n", warmupResult.Error)
-			}
-			return nil, fmt.Errorf("warm-up run failed: %s", warmupResult.Error)
+	reporter.BenchmarkStarted(config)
+	if session.Number > 1 {
+		reporter.Resumed(session.Number, result.Runs)
+	}
+
+	// Measure the shell's spawn time before anything else runs
+	if config.Calibrate && config.ShellName() != ShellNone {
+		overhead, err := calibrateShell(ctx, config)
+		reporter.CalibrationFinished(config.ShellName(), overhead, err)
+		if err != nil {
+			return nil, fmt.Errorf("shell calibration failed: %w", err)
 		}
-		fmt.Println()
+		result.ShellOverhead = overhead
 	}
 
-	for i := 1; i <= config.Runs; i++ {
-		if config.Debug {
-			fmt.Printf("Run %d/%d: (streaming output)\n", i, config.Runs)
-		} else {
-			fmt.Printf("Run %d/%d: ", i, config.Runs)
+	// Execute setup hook once before anything else
+	if config.Setup != "" {
+		reporter.HookStarted(HookSetup)
+		err := runHook(ctx, config.Setup, config)
+		reporter.HookFinished(HookSetup, err)
+		if err != nil {
+			runCleanupHook(config, result, reporter)
+			return nil, fmt.Errorf("setup hook failed: %w", err)
 		}
+	}
 
-		runResult := executeCommand(i, config.Command, config.Debug)
-		result.Runs = append(result.Runs, runResult)
+	// Start the service the runs depend on once setup is done
+	var svc *service
+	if config.Service != "" {
+		var err error
+		if svc, err = startBenchmarkService(ctx, config, result, reporter); err != nil {
+			runCleanupHook(config, result, reporter)
+			return nil, err
+		}
+	}
+
+	// Execute warm-up runs if enabled; an interruption skips straight to the report.
+	// A resumed benchmark that already has all its runs needs no warm-up.
+	if _, done := stopReason(config, result.Runs, result.ShellOverhead, time.Now()); !done {
+		if err := runWarmup(ctx, config, result, reporter); err != nil && ctx.Err() == nil {
+			stopBenchmarkService(svc, result, reporter)
+			runCleanupHook(config, result, reporter)
+			return nil, err
+		}
+	}
 
-		if runResult.Success {
-			if config.Debug {
-				fmt.Printf("Run %d/%d: ✓ Completed in %v\n", i, config.Runs, runResult.Duration)
-			} else {
-				fmt.Printf("✓ Completed in %v\n", runResult.Duration)
+	// Sample the background load before the first run, then during each run
+	monitor := newHostMonitor()
+	var hostSample HostSample
+	if monitor != nil {
+		if svc != nil {
+			// The service may have left caliper's process tree by daemonizing
+			monitor.excludeGroup(svc.cmd.Process.Pid)
+		}
+		hostSample = monitor.sampleIdle(ctx)
+		result.HostSamples = append(result.HostSamples, hostSample)
+		reporter.HostSampled(hostSample)
+	}
+
+	measureStart := time.Now()
+	for i := len(result.Runs) + 1; ; i++ {
+		if ctx.Err() != nil {
+			result.Interrupted = true
+			result.StopReason = StopReasonInterrupted
+			break
+		}
+
+		reason, stop := stopReason(config, result.Runs, result.ShellOverhead, measureStart)
+		if stop {
+			result.StopReason = reason
+			break
+		}
+
+		if config.RequireQuietHost && monitor != nil && hostSample.Noisy() {
+			quiet, err := monitor.waitForQuiet(ctx, hostSample, reporter)
+			result.HostSamples = append(result.HostSamples, quiet)
+			reporter.HostSampled(quiet)
+			if err != nil {
+				stopBenchmarkService(svc, result, reporter)
+				runCleanupHook(config, result, reporter)
+				return nil, err
 			}
-		} else {
-			if config.Debug {
-				fmt.Printf("Run %d/%d: ✗ Failed: %s\n", i, config.Runs, runResult.Error)
-			} else {
-				fmt.Printf("✗ Failed: %s\n", runResult.Error)
+			hostSample = quiet
+		}
+
+		event := RunEvent{Number: i, Label: runLabel(i, config)}
+		reporter.RunStarted(event)
+		runResult, hookFailures := executeRunWithRetries(ctx, i, fmt.Sprintf("run-%d", i), config)
+		runResult.Session = session.Number
+		reporter.RunFinished(event, runResult, hookFailures)
+		if monitor != nil {
+			hostSample = monitor.sample(i)
+			result.HostSamples = append(result.HostSamples, hostSample)
+			reporter.HostSampled(hostSample)
+		}
+
+		// The run in progress when interrupted is discarded
+		if runResult.Status == RunStatusInterrupted {
+			continue
+		}
+		result.Runs = append(result.Runs, runResult)
+		result.HookFailures = append(result.HookFailures, hookFailures...)
+		session.Runs++
+
+		if cp != nil {
+			if err := cp.appendRun(runResult, hookFailures); err != nil {
+				stopBenchmarkService(svc, result, reporter)
+				runCleanupHook(config, result, reporter)
+				return nil, err
 			}
 		}
 	}
 
+	stopBenchmarkService(svc, result, reporter)
+	runCleanupHook(config, result, reporter)
+
 	result.EndTime = time.Now()
 	result.TotalDuration = result.EndTime.Sub(result.StartTime)
 
-	// Calculate statistics
-	successCount := 0
-	durations := make([]float64, 0, config.Runs)
-
-	for _, run := range result.Runs {
-		if run.Success {
-			successCount++
-			durations = append(durations, run.Duration.Seconds())
+	if cp != nil && !result.Interrupted {
+		if err := cp.finish(result.EndTime); err != nil {
+			return nil, err
 		}
 	}
 
-	result.SuccessRate = (float64(successCount) / float64(config.Runs)) * 100.0
+	result.calculateStatistics()
+
+	reporter.BenchmarkFinished(result)
+	return result, nil
+}
+
+// calculateStatistics computes the success rate, flakiness and statistics of
+// the measured runs and flags the outlier runs
+func (r *Result) calculateStatistics() {
+	durations := successfulDurations(r.Runs)
+	if len(r.Runs) > 0 {
+		r.SuccessRate = (float64(len(durations)) / float64(len(r.Runs))) * 100.0
+	}
+	r.Flakiness = flakiness(r.Runs)
+
+	// Statistics are reported without the shell's spawn time when calibrated
+	durations = subtractOverhead(durations, r.ShellOverhead)
 
 	if len(durations) > 0 {
-		result.Stats = CalculateStatistics(durations)
+		lower, upper, ok := OutlierFences(durations, r.Config.outlierMethod())
+		kept := durations
+		if ok {
+			kept = flagOutliers(r.Runs, durations, lower, upper)
+		}
+		trim := r.Config.TrimOutliers && len(kept) < len(durations)
+		measured := durations
+		if trim {
+			measured = kept
+		}
+		r.Stats = CalculateStatistics(measured)
+		r.Stats.Trend = fitTrend(measuredRunNumbers(r.Runs, trim), measured, r.Stats.Mean)
+		r.Stats.calculatePercentiles(measured, r.Config.Percentiles)
+		r.Stats.calculateConfidenceIntervals(measured, r.Config.confidence())
+
+		// The relative CI is the one the adaptive mode checked (see adaptiveDurations)
+		statistic := r.Config.CIStatistic
+		if statistic == "" {
+			statistic = CIStatisticMean
+		}
+		if ci := RelativeCI(measured, statistic, r.Config.confidence()); !math.IsInf(ci, 1) {
+			r.RelativeCI = ci
+		}
+		r.Stats.Resources = CalculateResourceStatistics(r.Runs)
+		r.Stats.OutlierMethod = r.Config.outlierMethod()
+		if ok {
+			r.Stats.Outliers = len(durations) - len(kept)
+			r.Stats.LowerFence = lower
+			r.Stats.UpperFence = upper
+			r.Stats.Trimmed = trim
+		}
+		r.ColdFirstRun = detectColdFirstRun(r.Runs)
 	}
+}
 
-	return result, nil
+// runLabel returns the progress prefix for a measured run
+func runLabel(runNumber int, config Config) string {
+	if config.Adaptive() {
+		return fmt.Sprintf("Run %d (max %d)", runNumber, config.MaxRuns)
+	}
+	return fmt.Sprintf("Run %d/%d", runNumber, config.Runs)
+}
+
+// ciProgress returns the current relative confidence interval in adaptive mode
+func ciProgress(config Config, runs []RunResult, overhead time.Duration) string {
+	if !config.Adaptive() {
+		return ""
+	}
+	ci := RelativeCI(adaptiveDurations(config, runs, overhead), config.CIStatistic, config.confidence())
+	if math.IsInf(ci, 1) {
+		return ""
+	}
+	return fmt.Sprintf(" (CI ±%.1f%%, target ±%.1f%%)", ci*100, config.TargetCI*100)
 }
 
-// executeCommand runs a single benchmark iteration
-func executeCommand(runNumber int, command string, debug bool) RunResult {
+// executeCommand runs a single benchmark iteration, capturing its output under logName
+func executeCommand(ctx context.Context, runNumber int, logName string, config Config) RunResult {
 	result := RunResult{
 		RunNumber: runNumber,
+		ExitCode:  -1,
 	}
 
-	// Use bash to execute the command (supports && and other shell features)
-	cmd := exec.Command("bash", "-c", command)
+	output, err := newRunOutput(config, logName)
+	if err != nil {
+		result.Status = RunStatusFailed
+		result.Error = err.Error()
+		return result
+	}
 
-	// Stream stdout/stderr when debug is enabled
-	if debug {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	runCtx := ctx
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	cmd, err := newShellCommand(runCtx, config.ShellName(), config.Command, config.Debug)
+	if err != nil {
+		result.Status = RunStatusFailed
+		result.Error = err.Error()
+		return result
 	}
+	config.applyEnvironment(cmd.Cmd)
+	cmd.Stdout = output.stdout(config.Debug)
+	cmd.Stderr = output.stderr(config.Debug)
 
 	startTime := time.Now()
-	err := cmd.Run()
+	err = cmd.Run()
 	result.Duration = time.Since(startTime)
+	output.close(&result, config.StderrTailLines)
 
-	if err != nil {
+	// ProcessState is set whenever the process was started, even on non-zero exit
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+		result.Resources = resourceUsage(cmd.ProcessState)
+	}
+
+	// An exit code is only available when the process exited normally (not killed by a signal)
+	exited := result.ExitCode >= 0 && !errors.Is(err, exec.ErrWaitDelay)
+
+	switch {
+	case ctx.Err() != nil:
 		result.Success = false
-		result.Error = err.Error()
-	} else {
+		result.Status = RunStatusInterrupted
+		result.Error = "interrupted"
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		result.Success = false
+		result.Status = RunStatusTimedOut
+		result.Error = fmt.Sprintf("timed out after %s", config.Timeout)
+	case exited && config.exitCodeExpected(result.ExitCode):
 		result.Success = true
+		result.Status = RunStatusSuccess
+	case exited && config.IgnoreFailure:
+		result.Success = true
+		result.Status = RunStatusSuccess
+		result.Error = fmt.Sprintf("exit code %d (ignored)", result.ExitCode)
+	case err != nil:
+		result.Success = false
+		result.Status = RunStatusFailed
+		result.Error = err.Error()
+	default:
+		// Exit code 0 when only other codes are expected
+		result.Success = false
+		result.Status = RunStatusFailed
+		result.Error = fmt.Sprintf("unexpected exit code %d", result.ExitCode)
 	}
 
 	return result
 }
+
+// exitCodeExpected reports whether a run exiting with code counts as successful
+func (c Config) exitCodeExpected(code int) bool {
+	if len(c.ExpectedExitCodes) == 0 {
+		return code == 0
+	}
+	for _, expected := range c.ExpectedExitCodes {
+		if code == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// shellCommand is a command running in its own process group. After an
+// interruption, a timer kills the group once the grace period is over; Wait
+// stops it, so that it never fires at a group that has been reaped.
+type shellCommand struct {
+	*exec.Cmd
+	pgid int
+
+	mu        sync.Mutex
+	killTimer *time.Timer // Set when the command was interrupted
+	exited    bool        // Set when Wait returned
+}
+
+// newShellCommand prepares a command run through shell (or directly with ShellNone)
+// in its own process group
+func newShellCommand(ctx context.Context, shell, command string, debug bool) (*shellCommand, error) {
+	args, err := commandArgs(shell, command)
+	if err != nil {
+		return nil, err
+	}
+	cmd := &shellCommand{Cmd: exec.CommandContext(ctx, args[0], args[1:]...)}
+
+	// Run in a separate process group so the whole tree can be killed on timeout,
+	// not just the shell process (which would leave e.g. rustc children running)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		cmd.pgid = -cmd.Process.Pid
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return killGroup(cmd.pgid, syscall.SIGKILL)
+		}
+
+		// Interrupted: forward SIGINT so the command can exit cleanly, then
+		// make sure nothing in the group outlives the grace period
+		cmd.mu.Lock()
+		cmd.killTimer = time.AfterFunc(interruptGracePeriod, func() {
+			cmd.mu.Lock()
+			defer cmd.mu.Unlock()
+			if !cmd.exited {
+				killGroup(cmd.pgid, syscall.SIGKILL)
+			}
+		})
+		cmd.mu.Unlock()
+		return killGroup(cmd.pgid, syscall.SIGINT)
+	}
+	cmd.WaitDelay = killWaitDelay
+
+	// Stream stdout/stderr when debug is enabled
+	if debug {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	return cmd, nil
+}
+
+// Run starts the command and waits for it to complete
+func (c *shellCommand) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Wait waits for the command to exit and stops the interruption's kill timer.
+// What is left of an interrupted group once the command exited, e.g. children
+// that ignored SIGINT, is killed right away instead.
+func (c *shellCommand) Wait() error {
+	err := c.Cmd.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.exited = true
+	if c.killTimer != nil && c.killTimer.Stop() {
+		killGroup(c.pgid, syscall.SIGKILL)
+	}
+	return err
+}
+
+// killGroup signals a process group. A group that is already gone counts as
+// done rather than as a failure to cancel the command.
+func killGroup(pgid int, signal syscall.Signal) error {
+	if err := syscall.Kill(pgid, signal); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+	return nil
+}
+
+// runCleanupHook executes the cleanup hook and records a failure on the result
+func runCleanupHook(config Config, result *Result, reporter Reporter) {
+	if config.Cleanup == "" {
+		return
+	}
+
+	// Cleanup runs even after an interruption, so it gets a fresh context
+	reporter.HookStarted(HookCleanup)
+	err := runHook(context.Background(), config.Cleanup, config)
+	reporter.HookFinished(HookCleanup, err)
+	if err != nil {
+		result.HookFailures = append(result.HookFailures, HookFailure{Hook: HookCleanup, Error: err.Error()})
+	}
+}
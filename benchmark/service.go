@@ -0,0 +1,404 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultReadyTimeout is how long a service may take to pass its readiness probe
+const DefaultReadyTimeout = 30 * time.Second
+
+// Readiness polling. The interval bounds the precision of startup measurements
+// for TCP and HTTP probes; output probes are checked as soon as a line is written.
+const (
+	readyPollInterval = 10 * time.Millisecond
+	probeTimeout      = time.Second
+)
+
+// errNotReady is returned when a service did not pass its probe within the timeout
+var errNotReady = errors.New("not ready")
+
+// ReadinessProbe tells when a service is ready to accept work. Every
+// configured check must pass; with none configured a service is ready as
+// soon as it started.
+type ReadinessProbe struct {
+	TCP     string        // host:port that accepts connections once ready
+	HTTP    string        // URL that answers 200 OK once ready
+	Output  string        // Regular expression matched against each line of stdout and stderr
+	Timeout time.Duration // How long to wait for readiness (default 30s)
+}
+
+// Configured reports whether any check is set
+func (p ReadinessProbe) Configured() bool {
+	return p.TCP != "" || p.HTTP != "" || p.Output != ""
+}
+
+// Validate checks the address, URL and pattern of the probe
+func (p ReadinessProbe) Validate() error {
+	if p.TCP != "" {
+		if _, _, err := net.SplitHostPort(p.TCP); err != nil {
+			return fmt.Errorf("invalid TCP readiness address %q: %w", p.TCP, err)
+		}
+	}
+	if p.HTTP != "" {
+		u, err := url.Parse(p.HTTP)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid HTTP readiness URL %q: expected http://host[:port]/path", p.HTTP)
+		}
+	}
+	if p.Output != "" {
+		if _, err := regexp.Compile(p.Output); err != nil {
+			return fmt.Errorf("invalid output readiness pattern: %w", err)
+		}
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("readiness timeout must not be negative")
+	}
+	return nil
+}
+
+// Description summarises the checks, e.g. "TCP localhost:8080, output /listening/"
+func (p ReadinessProbe) Description() string {
+	var checks []string
+	if p.TCP != "" {
+		checks = append(checks, "TCP "+p.TCP)
+	}
+	if p.HTTP != "" {
+		checks = append(checks, "HTTP 200 from "+p.HTTP)
+	}
+	if p.Output != "" {
+		checks = append(checks, "output /"+p.Output+"/")
+	}
+	if len(checks) == 0 {
+		return "none"
+	}
+	return strings.Join(checks, ", ")
+}
+
+// withServiceDefaults fills in unset readiness settings
+func (c Config) withServiceDefaults() Config {
+	if c.Readiness.Timeout <= 0 {
+		c.Readiness.Timeout = DefaultReadyTimeout
+	}
+	return c
+}
+
+// ValidateService checks the service and readiness settings
+func (c Config) ValidateService() error {
+	if err := c.Readiness.Validate(); err != nil {
+		return err
+	}
+	if c.MeasureStartup {
+		if c.Service != "" {
+			return fmt.Errorf("a service cannot be combined with measuring startup: the command itself is the service")
+		}
+		if !c.Readiness.Configured() {
+			return fmt.Errorf("measuring startup requires a readiness probe")
+		}
+	} else if c.Readiness.Configured() && c.Service == "" {
+		return fmt.Errorf("a readiness probe requires a service or measuring startup")
+	}
+	return nil
+}
+
+// runTimeout returns the limit after which a run counts as timed out
+func (c Config) runTimeout() time.Duration {
+	if c.MeasureStartup {
+		return c.Readiness.Timeout
+	}
+	return c.Timeout
+}
+
+// service is a command running in the background while the benchmark proceeds
+type service struct {
+	cmd     *shellCommand
+	cancel  context.CancelFunc
+	output  *runOutput
+	started time.Time
+	matched chan struct{} // Closed when the output probe matched (nil without one)
+	exited  chan struct{} // Closed when the process exited
+	err     error         // Exit error, valid once exited is closed
+}
+
+// startService starts command in its own process group, capturing its output
+// under logName. The service keeps running until stop is called, whatever
+// happens to the benchmark's context.
+func startService(command, shell, logName string, config Config) (*service, error) {
+	output, err := newRunOutput(config, logName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd, err := newShellCommand(ctx, shell, command, config.Debug)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	config.applyEnvironment(cmd.Cmd)
+
+	svc := &service{cmd: cmd, cancel: cancel, output: output, exited: make(chan struct{})}
+	stdout, stderr := output.stdout(config.Debug), output.stderr(config.Debug)
+	if config.Readiness.Output != "" {
+		// Both streams report to the same channel; lines are split per stream
+		svc.matched = make(chan struct{})
+		signal := &readySignal{ch: svc.matched}
+		pattern := regexp.MustCompile(config.Readiness.Output)
+		stdout = withWriter(stdout, &lineMatcher{pattern: pattern, signal: signal})
+		stderr = withWriter(stderr, &lineMatcher{pattern: pattern, signal: signal})
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	svc.started = time.Now()
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, err
+	}
+	go func() {
+		svc.err = cmd.Wait()
+		close(svc.exited)
+	}()
+	return svc, nil
+}
+
+// waitReady polls the probe until every check passed and returns the time
+// since the service started. It fails when the service exits first, the
+// probe's timeout elapses (errNotReady) or ctx is cancelled.
+func (s *service) waitReady(ctx context.Context, probe ReadinessProbe) (time.Duration, error) {
+	deadline := time.NewTimer(probe.Timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	tcpReady := probe.TCP == ""
+	httpReady := probe.HTTP == ""
+	outputReady := probe.Output == ""
+	matched := s.matched
+	for {
+		if !outputReady {
+			select {
+			case <-matched:
+				outputReady = true
+				matched = nil // Closed channels would wake the loop continuously
+			default:
+			}
+		}
+		if outputReady && !tcpReady {
+			tcpReady = tcpProbe(probe.TCP)
+		}
+		if outputReady && tcpReady && !httpReady {
+			httpReady = httpProbe(probe.HTTP)
+		}
+		if outputReady && tcpReady && httpReady {
+			return time.Since(s.started), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-s.exited:
+			return 0, fmt.Errorf("exited before becoming ready: %s", s.exitDescription())
+		case <-deadline.C:
+			return 0, errNotReady
+		case <-matched:
+		case <-ticker.C:
+		}
+	}
+}
+
+// running reports whether the service has not exited yet
+func (s *service) running() bool {
+	select {
+	case <-s.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// stop interrupts the service's process group (killing it after the grace
+// period), waits for it to exit and finishes its logs on result
+func (s *service) stop(result *RunResult, tailLines int) {
+	s.cancel()
+	<-s.exited
+	s.output.close(result, tailLines)
+	if s.cmd.ProcessState != nil {
+		result.ExitCode = s.cmd.ProcessState.ExitCode()
+		result.Resources = resourceUsage(s.cmd.ProcessState)
+	}
+}
+
+// exitDescription describes how the exited service ended
+func (s *service) exitDescription() string {
+	if s.err != nil {
+		return s.err.Error()
+	}
+	return "exit status 0"
+}
+
+// tcpProbe reports whether address accepts connections
+func tcpProbe(address string) bool {
+	conn, err := net.DialTimeout("tcp", address, probeTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// httpProbe reports whether rawURL answers 200 OK
+func httpProbe(rawURL string) bool {
+	client := http.Client{Timeout: probeTimeout}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// readySignal closes its channel once, when the first stream matches
+type readySignal struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func (r *readySignal) fire() {
+	r.once.Do(func() { close(r.ch) })
+}
+
+// lineMatcher matches each line written to it against pattern. A partial
+// line is matched too, so prompts without a trailing newline are seen.
+type lineMatcher struct {
+	pattern *regexp.Regexp
+	signal  *readySignal
+	line    []byte
+	done    bool
+}
+
+func (m *lineMatcher) Write(p []byte) (int, error) {
+	n := len(p)
+	if m.done {
+		return n, nil
+	}
+	for len(p) > 0 {
+		end := len(p)
+		newline := false
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			end, newline = i, true
+		}
+		if room := tailBufferSize - len(m.line); room > 0 {
+			m.line = append(m.line, p[:min(end, room)]...)
+		}
+		if m.pattern.Match(m.line) {
+			m.done = true
+			m.signal.fire()
+			return n, nil
+		}
+		if newline {
+			m.line = m.line[:0]
+			end++
+		}
+		p = p[end:]
+	}
+	return n, nil
+}
+
+// withWriter adds extra to the writers of w (which may be nil)
+func withWriter(w io.Writer, extra io.Writer) io.Writer {
+	if w == nil {
+		return extra
+	}
+	return io.MultiWriter(w, extra)
+}
+
+// startBenchmarkService starts the configured service and waits until it is ready
+func startBenchmarkService(ctx context.Context, config Config, result *Result, reporter Reporter) (*service, error) {
+	reporter.ServiceStarted(config.Service)
+	svc, err := startService(config.Service, config.hookShell(), "service", config)
+	if err == nil {
+		result.ServiceReady, err = svc.waitReady(ctx, config.Readiness)
+		if errors.Is(err, errNotReady) {
+			err = fmt.Errorf("not ready after %s (%s)", config.Readiness.Timeout, config.Readiness.Description())
+		}
+	}
+	reporter.ServiceReady(result.ServiceReady, err)
+	if err != nil {
+		if svc != nil {
+			svc.stop(&RunResult{}, config.StderrTailLines)
+		}
+		return nil, fmt.Errorf("service failed: %w", err)
+	}
+	return svc, nil
+}
+
+// stopBenchmarkService stops the service started for the benchmark (if any)
+// and records a failure if it exited on its own while the runs were going on
+func stopBenchmarkService(svc *service, result *Result, reporter Reporter) {
+	if svc == nil {
+		return
+	}
+	exitedEarly := !svc.running()
+	var run RunResult
+	svc.stop(&run, result.Config.StderrTailLines)
+
+	var err error
+	if exitedEarly {
+		err = fmt.Errorf("exited during the benchmark: %s", svc.exitDescription())
+		result.HookFailures = append(result.HookFailures, HookFailure{Hook: HookService, Error: err.Error()})
+	}
+	reporter.ServiceStopped(err)
+}
+
+// executeStartup times one start of the command as a service, from launch
+// until its readiness probe passes, then stops it
+func executeStartup(ctx context.Context, runNumber int, logName string, config Config) RunResult {
+	result := RunResult{
+		RunNumber: runNumber,
+		ExitCode:  -1,
+	}
+
+	svc, err := startService(config.Command, config.ShellName(), logName, config)
+	if err != nil {
+		result.Status = RunStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	readyAfter, err := svc.waitReady(ctx, config.Readiness)
+	exitedEarly := !svc.running()
+	svc.stop(&result, config.StderrTailLines)
+	if !exitedEarly {
+		// The exit code of a service we stopped says nothing about the run
+		result.ExitCode = -1
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		result.Status = RunStatusInterrupted
+		result.Error = "interrupted"
+	case errors.Is(err, errNotReady):
+		result.Status = RunStatusTimedOut
+		result.Error = fmt.Sprintf("not ready after %s", config.Readiness.Timeout)
+	case err != nil:
+		result.Status = RunStatusFailed
+		result.Error = err.Error()
+	default:
+		result.Success = true
+		result.Status = RunStatusSuccess
+		result.Duration = readyAfter
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Shell selection
+const (
+	DefaultShell = "bash" // Shell used when Config.Shell is empty
+	ShellNone    = "none" // Split the command into arguments and execute it directly
+)
+
+// calibrationRuns is the number of empty shell invocations timed to estimate spawn overhead
+const calibrationRuns = 20
+
+// ShellName returns the shell used to run the benchmarked command
+func (c Config) ShellName() string {
+	if c.Shell == "" {
+		return DefaultShell
+	}
+	return c.Shell
+}
+
+// hookShell returns the shell used for hooks. Hooks always need a shell, so
+// they fall back to sh when the command itself is executed directly.
+func (c Config) hookShell() string {
+	if c.ShellName() == ShellNone {
+		return "sh"
+	}
+	return c.ShellName()
+}
+
+// ValidateShell checks that the configured shell can be used to run the command
+func (c Config) ValidateShell() error {
+	if c.ShellName() == ShellNone {
+		args, err := SplitCommand(c.Command)
+		if err != nil {
+			return fmt.Errorf("cannot run %q without a shell: %w", c.Command, err)
+		}
+		if _, err := exec.LookPath(args[0]); err != nil {
+			return fmt.Errorf("cannot run %q without a shell: %w", c.Command, err)
+		}
+		return nil
+	}
+
+	if _, err := exec.LookPath(c.ShellName()); err != nil {
+		return fmt.Errorf("shell %q not found: %w", c.ShellName(), err)
+	}
+	return nil
+}
+
+// commandArgs returns the argv that runs command with the given shell
+func commandArgs(shell, command string) ([]string, error) {
+	if shell == ShellNone {
+		return SplitCommand(command)
+	}
+	return []string{shell, "-c", command}, nil
+}
+
+// SplitCommand splits a command line into arguments the way a POSIX shell
+// would for a simple command: whitespace separates words, single quotes are
+// literal, double quotes allow \" \\ \$ and \` escapes, and a backslash
+// outside quotes escapes the next character. Pipes, redirections, variables
+// and globs are not interpreted.
+func SplitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '\\':
+			i++
+			if i >= len(command) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			current.WriteByte(command[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			current.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`", command[i+1]) >= 0 {
+					i++
+				}
+				current.WriteByte(command[i])
+			}
+			if i >= len(command) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
+
+// calibrateShell estimates the time spent starting the shell by timing an
+// empty command, and returns the median of several invocations
+func calibrateShell(ctx context.Context, config Config) (time.Duration, error) {
+	samples := make([]time.Duration, 0, calibrationRuns)
+	for i := 0; i < calibrationRuns; i++ {
+		cmd, err := newShellCommand(ctx, config.ShellName(), "", false)
+		if err != nil {
+			return 0, err
+		}
+		config.applyEnvironment(cmd.Cmd)
+
+		start := time.Now()
+		if err := cmd.Run(); err != nil {
+			return 0, fmt.Errorf("empty %s command failed: %w", config.ShellName(), err)
+		}
+		samples = append(samples, time.Since(start))
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return samples[len(samples)/2], nil
+}
+
+// subtractOverhead returns the durations (in seconds) minus the shell overhead,
+// clamped at zero
+func subtractOverhead(durations []float64, overhead time.Duration) []float64 {
+	if overhead <= 0 {
+		return durations
+	}
+
+	corrected := make([]float64, len(durations))
+	for i, d := range durations {
+		corrected[i] = d - overhead.Seconds()
+		if corrected[i] < 0 {
+			corrected[i] = 0
+		}
+	}
+	return corrected
+}
@@ -0,0 +1,96 @@
+package benchmark
+
+import (
+	"math"
+	"sort"
+)
+
+// welchTTest tests whether two samples, summarised by their mean, sample
+// standard deviation and size, have the same mean without assuming equal
+// variances. It returns the t statistic, the Welch–Satterthwaite degrees of
+// freedom, the two-sided p-value and the standard error of the difference.
+func welchTTest(a, b SampleSummary) (t, df, p, stdErr float64) {
+	varA := a.StdDev * a.StdDev / float64(a.N)
+	varB := b.StdDev * b.StdDev / float64(b.N)
+	stdErr = math.Sqrt(varA + varB)
+	diff := b.Mean - a.Mean
+
+	if stdErr == 0 {
+		// Both samples are constant: any difference is certain
+		df = float64(a.N + b.N - 2)
+		if diff == 0 {
+			return 0, df, 1, 0
+		}
+		return math.Copysign(math.Inf(1), diff), df, 0, 0
+	}
+
+	t = diff / stdErr
+	df = (varA + varB) * (varA + varB) /
+		(varA*varA/float64(a.N-1) + varB*varB/float64(b.N-1))
+	p = 2 * (1 - studentTCDF(math.Abs(t), df))
+	return t, df, p, stdErr
+}
+
+// mannWhitneyUTest tests whether durations in one sample tend to be larger
+// than in the other, without assuming a distribution. It returns the smaller
+// of the two U statistics and the two-sided p-value from the normal
+// approximation with tie and continuity corrections.
+func mannWhitneyUTest(a, b []float64) (u, p float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	n := n1 + n2
+
+	type value struct {
+		duration float64
+		first    bool
+	}
+	values := make([]value, 0, len(a)+len(b))
+	for _, d := range a {
+		values = append(values, value{d, true})
+	}
+	for _, d := range b {
+		values = append(values, value{d, false})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].duration < values[j].duration })
+
+	// Rank the pooled values, giving ties their average rank
+	rankSum := 0.0
+	tieTerm := 0.0
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j].duration == values[i].duration {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if values[k].first {
+				rankSum += rank
+			}
+		}
+		ties := float64(j - i)
+		tieTerm += ties*ties*ties - ties
+		i = j
+	}
+
+	u1 := rankSum - n1*(n1+1)/2
+	u = math.Min(u1, n1*n2-u1)
+
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - tieTerm/(n*(n-1))))
+	if sigma == 0 {
+		return u, 1
+	}
+	z := (math.Abs(u1-n1*n2/2) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	return u, 2 * (1 - normalCDF(z))
+}
+
+// cohensD returns the difference of the means in units of the pooled
+// standard deviation (0 when both samples are constant)
+func cohensD(a, b SampleSummary) float64 {
+	pooled := math.Sqrt((float64(a.N-1)*a.StdDev*a.StdDev + float64(b.N-1)*b.StdDev*b.StdDev) / float64(a.N+b.N-2))
+	if pooled == 0 {
+		return 0
+	}
+	return (b.Mean - a.Mean) / pooled
+}
@@ -0,0 +1,113 @@
+package benchmark
+
+import (
+	"math"
+	"testing"
+)
+
+// Samples of the first two examples of Welch's t-test on Wikipedia
+var (
+	welchEqualA = []float64{27.5, 21.0, 19.0, 23.6, 17.0, 17.9, 16.9, 20.1, 21.9, 22.6, 23.1, 19.6, 19.0, 21.7, 21.4}
+	welchEqualB = []float64{27.1, 22.0, 20.8, 23.4, 23.4, 23.5, 25.8, 22.0, 24.8, 20.2, 21.9, 22.1, 22.9, 20.5, 24.4}
+
+	welchUnequalA = []float64{17.2, 20.9, 22.6, 18.1, 21.7, 21.4, 23.5, 24.2, 14.7, 21.8}
+	welchUnequalB = []float64{21.5, 22.8, 21.0, 23.0, 21.6, 23.6, 22.5, 20.7, 23.4, 21.8, 20.7, 21.7, 21.5, 22.5, 23.6, 21.5, 22.5, 23.5, 21.5, 21.8}
+)
+
+func TestWelchTTest(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []float64
+		t, df, p float64
+		stdErr   float64
+	}{
+		// Wikipedia: t = 2.46, df = 24.99, p = 0.021
+		{"equal variances", welchEqualA, welchEqualB, 2.455356398286006, 24.98852929023142, 0.02137800146286173, 0.8824245100137552},
+		// Wikipedia: t = 1.57, df = 9.90, p = 0.149
+		{"unequal variances", welchUnequalA, welchUnequalB, 1.5654335235985073, 9.904741248650831, 0.14884169660532542, 0.9741710376141942},
+	}
+	for _, tt := range tests {
+		tStat, df, p, stdErr := welchTTest(summarizeDurations(tt.a), summarizeDurations(tt.b))
+		if math.Abs(tStat-tt.t) > 1e-9 || math.Abs(df-tt.df) > 1e-9 || math.Abs(stdErr-tt.stdErr) > 1e-9 {
+			t.Errorf("%s: t = %.9f, df = %.9f, stdErr = %.9f, want %.9f, %.9f, %.9f", tt.name, tStat, df, stdErr, tt.t, tt.df, tt.stdErr)
+		}
+		if math.Abs(p-tt.p) > 1e-8 {
+			t.Errorf("%s: p = %.10f, want %.10f", tt.name, p, tt.p)
+		}
+	}
+}
+
+func TestWelchTTestConstantSamples(t *testing.T) {
+	same := SampleSummary{N: 3, Mean: 2}
+	if tStat, _, p, _ := welchTTest(same, same); tStat != 0 || p != 1 {
+		t.Errorf("identical constant samples: t = %g, p = %g, want 0, 1", tStat, p)
+	}
+	if tStat, _, p, _ := welchTTest(same, SampleSummary{N: 3, Mean: 1}); !math.IsInf(tStat, -1) || p != 0 {
+		t.Errorf("different constant samples: t = %g, p = %g, want -Inf, 0", tStat, p)
+	}
+}
+
+func TestMannWhitneyUTest(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		u, p float64
+	}{
+		// SciPy's mannwhitneyu example with method="asymptotic": U = 17 (3 for the other sample)
+		{"no ties", []float64{19, 22, 16, 29, 24}, []float64{20, 11, 17, 12}, 3, 0.11134688653314041},
+		// Ranks 1, 3.5 (×4), 7.5 (×4), 10, 12 (×3): U1 = 26.5 - 21 = 5.5, tie term 60 + 60 + 24 = 144,
+		// σ = √(42/12 · (14 - 144/156)), z = (15.5 - 0.5)/σ
+		{"ties", []float64{1, 2, 2, 2, 3, 3}, []float64{2, 3, 3, 4, 5, 5, 5}, 5.5, 0.026609573211862768},
+		// Every value tied: no evidence of a difference
+		{"all tied", []float64{4, 4, 4}, []float64{4, 4}, 3, 1},
+	}
+	for _, tt := range tests {
+		u, p := mannWhitneyUTest(tt.a, tt.b)
+		if u != tt.u || math.Abs(p-tt.p) > 1e-12 {
+			t.Errorf("%s: U = %g, p = %.15f, want %g, %.15f", tt.name, u, p, tt.u, tt.p)
+		}
+		// The test is symmetric in its samples
+		if u2, p2 := mannWhitneyUTest(tt.b, tt.a); u2 != u || math.Abs(p2-p) > 1e-12 {
+			t.Errorf("%s swapped: U = %g, p = %g, want %g, %g", tt.name, u2, p2, u, p)
+		}
+	}
+}
+
+func TestCohensD(t *testing.T) {
+	a := SampleSummary{N: 10, Mean: 10, StdDev: 2}
+	b := SampleSummary{N: 10, Mean: 11, StdDev: 2}
+	if got := cohensD(a, b); math.Abs(got-0.5) > 1e-12 {
+		t.Errorf("cohensD = %g, want 0.5", got)
+	}
+	if got := cohensD(SampleSummary{N: 2, Mean: 1}, SampleSummary{N: 2, Mean: 2}); got != 0 {
+		t.Errorf("cohensD of constant samples = %g, want 0", got)
+	}
+}
+
+func TestDiffDurations(t *testing.T) {
+	tests := []struct {
+		name                string
+		baseline, candidate []float64
+		verdict             Verdict
+	}{
+		{"slower", welchEqualA, welchEqualB, VerdictSlower},
+		{"faster", welchEqualB, welchEqualA, VerdictFaster},
+		{"not significant", welchUnequalA, welchUnequalB, VerdictNoChange},
+		{"one run", []float64{1}, []float64{1, 2}, VerdictInsufficient},
+	}
+	for _, tt := range tests {
+		diff := DiffDurations(tt.baseline, tt.candidate, 0.95)
+		if diff.Verdict != tt.verdict {
+			t.Errorf("%s: verdict %q, want %q", tt.name, diff.Verdict, tt.verdict)
+		}
+	}
+
+	// The interval of the difference is mean difference ± t(0.975, df) · stdErr
+	diff := DiffDurations(welchEqualA, welchEqualB, 0.95)
+	half := studentTQuantile(0.975, diff.WelchDF) * 0.8824245100137552
+	if math.Abs(diff.Difference-2.166666666666668) > 1e-9 ||
+		math.Abs(diff.DifferenceCI.Low-(diff.Difference-half)) > 1e-9 ||
+		math.Abs(diff.DifferenceCI.High-(diff.Difference+half)) > 1e-9 {
+		t.Errorf("difference = %g (%g–%g), want 2.1667 ± %g", diff.Difference, diff.DifferenceCI.Low, diff.DifferenceCI.High, half)
+	}
+}
@@ -1,23 +1,52 @@
 package benchmark
 
 import (
+	"fmt"
 	"math"
 	"sort"
+	"strconv"
+)
+
+// Estimators used by CalculateStatistics, named in the JSON output so other
+// tools can interpret the values
+const (
+	StdDevEstimator     = "sample"   // Bessel-corrected standard deviation, divides by N-1
+	MADEstimator        = "unscaled" // Median of the absolute deviations from the median, not scaled to σ
+	PercentileEstimator = "linear"   // Linear interpolation between the closest ranks
 )
 
 // Statistics holds calculated statistical metrics
 type Statistics struct {
-	N              int     // Number of successful runs
-	Mean           float64 // Average duration in seconds
-	Median         float64 // Median duration in seconds
-	StdDev         float64 // Standard deviation in seconds
-	Min            float64 // Minimum duration in seconds
-	Max            float64 // Maximum duration in seconds
-	P90            float64 // 90th percentile in seconds
-	P95            float64 // 95th percentile in seconds
+	N           int                // Number of successful runs
+	Mean        float64            // Average duration in seconds
+	Median      float64            // Median duration in seconds
+	StdDev      float64            // Sample standard deviation in seconds (0 with a single run)
+	CV          float64            // Coefficient of variation: StdDev / Mean
+	MAD         float64            // Median absolute deviation in seconds
+	Min         float64            // Minimum duration in seconds
+	Max         float64            // Maximum duration in seconds
+	P90         float64            // 90th percentile in seconds
+	P95         float64            // 95th percentile in seconds
+	Percentiles []Percentile       // Additionally requested percentiles (see Config.Percentiles)
+	Trend       Trend              // Trend of the durations across the runs
+	Resources   ResourceStatistics // Resource usage aggregated over successful runs
+
+	// Outlier detection over the successful runs (see OutlierFences)
+	OutlierMethod OutlierMethod // Method the fences were computed with
+	Outliers      int           // Successful runs outside the fences
+	LowerFence    float64       // Durations below this are outliers, in seconds (0 when not computed)
+	UpperFence    float64       // Durations above this are outliers, in seconds (0 when not computed)
+	Trimmed       bool          // The statistics above exclude the outliers
+
+	// Confidence intervals of the mean and median (zero with fewer than two runs)
+	Confidence      float64            // Confidence level of the intervals, e.g. 0.95
+	MeanCI          ConfidenceInterval // Mean, from Student's t-distribution
+	MeanBootstrapCI ConfidenceInterval // Mean, percentile bootstrap
+	MedianCI        ConfidenceInterval // Median, percentile bootstrap
 }
 
-// CalculateStatistics computes all statistical metrics from duration data
+// CalculateStatistics computes all statistical metrics from duration data,
+// which must be in run order for the trend
 func CalculateStatistics(durations []float64) Statistics {
 	if len(durations) == 0 {
 		return Statistics{}
@@ -42,14 +71,20 @@ func CalculateStatistics(durations []float64) Statistics {
 	// Calculate median
 	stats.Median = percentile(sorted, 50)
 
-	// Calculate standard deviation
-	variance := 0.0
-	for _, d := range durations {
-		diff := d - stats.Mean
-		variance += diff * diff
+	// Calculate the sample standard deviation, as the runs are a sample of all possible runs
+	if len(durations) > 1 {
+		variance := 0.0
+		for _, d := range durations {
+			diff := d - stats.Mean
+			variance += diff * diff
+		}
+		variance /= float64(len(durations) - 1)
+		stats.StdDev = math.Sqrt(variance)
+	}
+	if stats.Mean > 0 {
+		stats.CV = stats.StdDev / stats.Mean
 	}
-	variance /= float64(len(durations))
-	stats.StdDev = math.Sqrt(variance)
+	stats.MAD = medianAbsoluteDeviation(sorted)
 
 	// Min and Max
 	stats.Min = sorted[0]
@@ -59,9 +94,70 @@ func CalculateStatistics(durations []float64) Statistics {
 	stats.P90 = percentile(sorted, 90)
 	stats.P95 = percentile(sorted, 95)
 
+	// Trend across the runs, e.g. thermal throttling. The durations are
+	// taken to be consecutive runs; a Result refits it on its run numbers.
+	stats.Trend = fitTrend(consecutiveRunNumbers(len(durations)), durations, stats.Mean)
+
 	return stats
 }
 
+// Percentile is the value of a requested percentile
+type Percentile struct {
+	Percentile float64 `json:"percentile"` // Percentile between 0 and 100, e.g. 99
+	Value      float64 `json:"value"`      // Duration in seconds
+}
+
+// Label returns the short name of the percentile, e.g. "P99.9"
+func (p Percentile) Label() string {
+	return "P" + strconv.FormatFloat(p.Percentile, 'f', -1, 64)
+}
+
+// ValidatePercentiles checks that each requested percentile is between 0 and 100
+func ValidatePercentiles(percentiles []float64) error {
+	for _, p := range percentiles {
+		if p < 0 || p > 100 || math.IsNaN(p) {
+			return fmt.Errorf("percentile %g must be between 0 and 100", p)
+		}
+	}
+	return nil
+}
+
+// calculatePercentiles fills in the requested percentiles of durations,
+// sorted and without duplicates
+func (s *Statistics) calculatePercentiles(durations []float64, percentiles []float64) {
+	if len(durations) == 0 || len(percentiles) == 0 {
+		return
+	}
+
+	sorted := make([]float64, len(durations))
+	copy(sorted, durations)
+	sort.Float64s(sorted)
+
+	requested := make([]float64, len(percentiles))
+	copy(requested, percentiles)
+	sort.Float64s(requested)
+
+	s.Percentiles = nil
+	for i, p := range requested {
+		if i > 0 && p == requested[i-1] {
+			continue
+		}
+		s.Percentiles = append(s.Percentiles, Percentile{Percentile: p, Value: percentile(sorted, p)})
+	}
+}
+
+// medianAbsoluteDeviation returns the median of the absolute deviations of
+// sorted from its median
+func medianAbsoluteDeviation(sorted []float64) float64 {
+	median := percentile(sorted, 50)
+	deviations := make([]float64, len(sorted))
+	for i, d := range sorted {
+		deviations[i] = math.Abs(d - median)
+	}
+	sort.Float64s(deviations)
+	return percentile(deviations, 50)
+}
+
 // percentile calculates the specified percentile from sorted data
 func percentile(sorted []float64, p float64) float64 {
 	if len(sorted) == 0 {
@@ -0,0 +1,124 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+)
+
+// Thresholds of the drift warning. A trend must be both statistically
+// significant and large enough to matter: with many quiet runs, even a drift
+// of a fraction of a percent is significant.
+const (
+	minTrendRuns      = 5    // Fewer runs give no trend
+	trendSignificance = 0.05 // The slope must differ from zero at this level
+	minTrendDrift     = 0.02 // The line must rise or fall by at least 2% of the mean over the runs
+)
+
+// Trend is the least-squares line of duration versus run number. A significant
+// slope means the runs were not measured under the same conditions, e.g. the
+// CPU heated up and throttled, or caches were still warming up.
+type Trend struct {
+	Runs  int     `json:"runs"`  // Number of runs the line was fitted to (0 with too few runs)
+	Slope float64 `json:"slope"` // Change of the duration per run in seconds
+	Drift float64 `json:"drift"` // Change from the first to the last run along the line, relative to the mean
+	P     float64 `json:"p"`     // Two-sided p-value of a zero slope (1 when not computed)
+}
+
+// Valid reports whether the trend was computed (it needs 5 runs or more)
+func (t Trend) Valid() bool {
+	return t.Runs > 0
+}
+
+// Drifting reports whether the runs got systematically slower or faster
+func (t Trend) Drifting() bool {
+	return t.Valid() && t.P < trendSignificance && math.Abs(t.Drift) >= minTrendDrift
+}
+
+// Direction describes the sign of the trend: "slower" or "faster"
+func (t Trend) Direction() string {
+	if t.Slope < 0 {
+		return "faster"
+	}
+	return "slower"
+}
+
+// DriftWarning explains a drifting trend and what may cause it
+func DriftWarning(t Trend) string {
+	if t.Slope < 0 {
+		return fmt.Sprintf("Runs got systematically faster: %s. Caches or the CPU frequency may still have been warming up; consider more warm-up runs (--warmup N or --warmup auto).", t)
+	}
+	return fmt.Sprintf("Runs got systematically slower: %s. The machine may be heating up and throttling, or filling a disk or cache; let it cool down between runs or pin the CPU frequency.", t)
+}
+
+// String describes the trend, e.g. "+12ms per run (+6.2% over the runs, p = 0.003)"
+func (t Trend) String() string {
+	return fmt.Sprintf("%s per run (%+.1f%% over the runs, p %s)", formatSignedDuration(t.Slope), t.Drift*100, FormatPValue(t.P))
+}
+
+// fitTrend fits a least-squares line to durations against the run numbers
+// they were measured in, and tests its slope with a t-test on n - 2 degrees of
+// freedom. Regressing on the run numbers rather than on the positions in the
+// slice keeps failed and trimmed runs from shifting the later ones.
+func fitTrend(runNumbers, durations []float64, mean float64) Trend {
+	n := len(durations)
+	if n < minTrendRuns || mean <= 0 {
+		return Trend{P: 1}
+	}
+
+	xMean := 0.0
+	for _, x := range runNumbers {
+		xMean += x
+	}
+	xMean /= float64(n)
+
+	sxx, sxy := 0.0, 0.0
+	for i, d := range durations {
+		dx := runNumbers[i] - xMean
+		sxx += dx * dx
+		sxy += dx * (d - mean)
+	}
+	if sxx == 0 {
+		return Trend{P: 1}
+	}
+	slope := sxy / sxx
+
+	sse := 0.0
+	for i, d := range durations {
+		residual := d - (mean + slope*(runNumbers[i]-xMean))
+		sse += residual * residual
+	}
+
+	span := runNumbers[n-1] - runNumbers[0]
+	trend := Trend{Runs: n, Slope: slope, Drift: slope * span / mean, P: 1}
+	df := float64(n - 2)
+	stdErr := math.Sqrt(sse / df / sxx)
+	switch {
+	case stdErr > 0:
+		trend.P = 2 * (1 - studentTCDF(math.Abs(slope/stdErr), df))
+	case slope != 0:
+		// The runs lie exactly on a sloped line
+		trend.P = 0
+	}
+	return trend
+}
+
+// consecutiveRunNumbers numbers n durations 1..n, for durations without run numbers
+func consecutiveRunNumbers(n int) []float64 {
+	numbers := make([]float64, n)
+	for i := range numbers {
+		numbers[i] = float64(i + 1)
+	}
+	return numbers
+}
+
+// measuredRunNumbers returns the run numbers of the durations the statistics
+// are computed from: the successful runs, without the outliers when trimmed
+func measuredRunNumbers(runs []RunResult, trimmed bool) []float64 {
+	numbers := make([]float64, 0, len(runs))
+	for _, run := range runs {
+		if run.Success && !(trimmed && run.Outlier) {
+			numbers = append(numbers, float64(run.RunNumber))
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,44 @@
+package benchmark
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFitTrend(t *testing.T) {
+	// Durations on the line 1 + 0.1·x, with run 4 missing
+	runNumbers := []float64{1, 2, 3, 5, 6, 7}
+	durations := []float64{1.1, 1.2, 1.3, 1.5, 1.6, 1.7}
+	mean := CalculateStatistics(durations).Mean
+	trend := fitTrend(runNumbers, durations, mean)
+	if math.Abs(trend.Slope-0.1) > 1e-12 || math.Abs(trend.Drift-0.6/mean) > 1e-12 || trend.P != 0 {
+		t.Errorf("trend = %+v, want slope 0.1, drift %g, p 0", trend, 0.6/mean)
+	}
+
+	// Textbook regression: x = 1..5, y = 2, 4, 5, 4, 5 gives slope 0.6,
+	// residual standard error √(2.4/3) and t = 0.6/√(0.8/10) = 2.1213 on 3 df
+	durations = []float64{2, 4, 5, 4, 5}
+	trend = fitTrend(consecutiveRunNumbers(5), durations, 4)
+	if math.Abs(trend.Slope-0.6) > 1e-12 || math.Abs(trend.P-0.12402706265) > 1e-9 {
+		t.Errorf("trend = %+v, want slope 0.6, p 0.12403", trend)
+	}
+
+	if trend := fitTrend(consecutiveRunNumbers(4), []float64{1, 2, 3, 4}, 2.5); trend.Valid() {
+		t.Errorf("trend of 4 runs = %+v, want none", trend)
+	}
+}
+
+func TestCalculateStatisticsTrendSkipsFailedRuns(t *testing.T) {
+	// A failed run must not shift the later runs along the x axis
+	var runs []RunResult
+	for n := 1; n <= 8; n++ {
+		run := RunResult{RunNumber: n, Success: n != 3, Duration: time.Duration(n) * 100 * time.Millisecond}
+		runs = append(runs, run)
+	}
+	result := &Result{Config: Config{OutlierMethod: OutlierNone}, Runs: runs}
+	result.calculateStatistics()
+	if trend := result.Stats.Trend; math.Abs(trend.Slope-0.1) > 1e-9 {
+		t.Errorf("slope = %g s/run, want 0.1", trend.Slope)
+	}
+}
@@ -0,0 +1,151 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Defaults for warm-up runs when they are not set explicitly
+const (
+	DefaultWarmupRuns      = 1
+	DefaultWarmupTolerance = 0.05 // Consecutive warm-up durations within 5% count as stable
+	DefaultMaxWarmupRuns   = 10
+)
+
+// coldOutlierFloor is the minimum relative slowdown of the first measured run
+// over the median of the others before it is flagged as a cold outlier
+const coldOutlierFloor = 0.05
+
+// ParseWarmup parses a --warmup value: a number of runs or "auto"
+func ParseWarmup(value string) (runs int, auto bool, err error) {
+	if value == "auto" {
+		return 0, true, nil
+	}
+
+	runs, err = strconv.Atoi(value)
+	if err != nil || runs < 0 {
+		return 0, false, fmt.Errorf("invalid --warmup %q: expected a number of runs or 'auto'", value)
+	}
+	return runs, false, nil
+}
+
+// withWarmupDefaults fills in unset warm-up settings
+func (c Config) withWarmupDefaults() Config {
+	if c.Warmup <= 0 {
+		c.Warmup = DefaultWarmupRuns
+	}
+	if c.WarmupTolerance <= 0 {
+		c.WarmupTolerance = DefaultWarmupTolerance
+	}
+	if c.MaxWarmupRuns <= 0 {
+		c.MaxWarmupRuns = DefaultMaxWarmupRuns
+	}
+	return c
+}
+
+// runWarmup executes the warm-up runs and records them on the result.
+// A failed or interrupted warm-up run aborts the warm-up with an error.
+func runWarmup(ctx context.Context, config Config, result *Result, reporter Reporter) error {
+	if config.SkipWarmup {
+		return nil
+	}
+
+	for i := 1; ; i++ {
+		event := RunEvent{Warmup: true, Number: i, Label: warmupLabel(i, config)}
+		reporter.RunStarted(event)
+		warmupResult, hookFailures := executeRunWithRetries(ctx, 0, fmt.Sprintf("warmup-%d", i), config)
+		warmupResult.Session = result.Sessions[len(result.Sessions)-1].Number
+		reporter.RunFinished(event, warmupResult, hookFailures)
+
+		if warmupResult.Status == RunStatusInterrupted {
+			return ctx.Err()
+		}
+		result.WarmupRuns = append(result.WarmupRuns, warmupResult)
+		result.HookFailures = append(result.HookFailures, hookFailures...)
+
+		if !warmupResult.Success {
+			return fmt.Errorf("warm-up run %d failed: %s", i, warmupResult.Error)
+		}
+
+		if config.WarmupAuto {
+			if warmupStable(result.WarmupRuns, config.WarmupTolerance) {
+				result.WarmupStable = true
+				break
+			}
+			if i >= config.MaxWarmupRuns {
+				break
+			}
+		} else if i >= config.Warmup {
+			break
+		}
+	}
+
+	reporter.WarmupFinished(result)
+	return nil
+}
+
+// warmupLabel returns the progress prefix for a warm-up run
+func warmupLabel(runNumber int, config Config) string {
+	switch {
+	case config.WarmupAuto:
+		return fmt.Sprintf("Warm-up %d (auto, max %d)", runNumber, config.MaxWarmupRuns)
+	case config.Warmup > 1:
+		return fmt.Sprintf("Warm-up %d/%d", runNumber, config.Warmup)
+	default:
+		return "Warm-up"
+	}
+}
+
+// warmupChange describes how the latest warm-up run compares to the previous one in auto mode
+func warmupChange(config Config, runs []RunResult) string {
+	if !config.WarmupAuto || len(runs) < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.1f%% vs previous)", relativeChange(runs[len(runs)-2].Duration, runs[len(runs)-1].Duration)*100)
+}
+
+// warmupStable reports whether the last two warm-up durations are within the tolerance
+func warmupStable(runs []RunResult, tolerance float64) bool {
+	if len(runs) < 2 {
+		return false
+	}
+	change := relativeChange(runs[len(runs)-2].Duration, runs[len(runs)-1].Duration)
+	return math.Abs(change) <= tolerance
+}
+
+// relativeChange returns (current - previous) / previous
+func relativeChange(previous, current time.Duration) float64 {
+	if previous <= 0 {
+		return math.Inf(1)
+	}
+	return float64(current-previous) / float64(previous)
+}
+
+// detectColdFirstRun reports whether the first measured run is a slow outlier
+// compared to the remaining successful runs, suggesting the warm-up was too short
+func detectColdFirstRun(runs []RunResult) bool {
+	if len(runs) == 0 || !runs[0].Success {
+		return false
+	}
+
+	rest := successfulDurations(runs[1:])
+	if len(rest) < 3 {
+		return false
+	}
+
+	sorted := make([]float64, len(rest))
+	copy(sorted, rest)
+	sort.Float64s(sorted)
+	median := percentile(sorted, 50)
+
+	// Median absolute deviation, scaled to be consistent with the standard deviation
+	mad := medianAbsoluteDeviation(sorted) / madConsistency
+
+	first := runs[0].Duration.Seconds()
+	threshold := math.Max(3*mad, coldOutlierFloor*median)
+	return first > sorted[len(sorted)-1] && first-median > threshold
+}
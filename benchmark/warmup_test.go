@@ -0,0 +1,32 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectColdFirstRun(t *testing.T) {
+	runs := func(ms ...int) []RunResult {
+		results := make([]RunResult, len(ms))
+		for i, m := range ms {
+			results[i] = RunResult{RunNumber: i + 1, Duration: time.Duration(m) * time.Millisecond, Success: true}
+		}
+		return results
+	}
+
+	tests := []struct {
+		name string
+		runs []RunResult
+		want bool
+	}{
+		{"cold first run", runs(150, 100, 101, 99, 100, 102), true},
+		{"within the spread", runs(104, 100, 96, 103, 97, 101), false},
+		{"noisy runs", runs(150, 100, 140, 60, 130, 70), false},
+		{"too few runs", runs(150, 100, 101), false},
+	}
+	for _, tt := range tests {
+		if got := detectColdFirstRun(tt.runs); got != tt.want {
+			t.Errorf("%s: detectColdFirstRun = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
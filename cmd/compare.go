@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/attunehq/caliper/benchmark"
+	"github.com/attunehq/caliper/matrix"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for compare command
+	compareConfidence string
+	compareJSON       string
+	compareMarkdown   string
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <baseline.json> <candidate.json>",
+	Short: "Compare two saved benchmark results for a significant difference",
+	Long: `Compare a candidate benchmark result against a baseline and report whether
+the difference is statistically significant.
+
+Both files are JSON outputs of caliper: either two single benchmark results,
+compared with Welch's t-test and the Mann–Whitney U test on their per-run
+durations, or two matrix summaries (*_summary.json), compared configuration by
+configuration with Welch's t-test on their statistics.
+
+The candidate counts as faster or slower only when every test rejects "no
+difference" at the significance level 1 - confidence.`,
+	Example: `  caliper compare results/main.json results/feature.json
+  caliper compare main_summary.json feature_summary.json --confidence 99% --markdown diff.md`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().StringVar(&compareConfidence, "confidence", "95%", "Confidence level of the difference's interval; 1 - confidence is the significance level of the tests")
+	compareCmd.Flags().StringVar(&compareJSON, "json", "", "Also save the comparison as JSON to this file")
+	compareCmd.Flags().StringVar(&compareMarkdown, "markdown", "", "Also save the comparison as a Markdown report to this file")
+
+	rootCmd.AddCommand(compareCmd)
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	level, err := benchmark.ParseConfidence(compareConfidence)
+	if err != nil {
+		return err
+	}
+
+	baselinePath, candidatePath := args[0], args[1]
+	baselineMatrix, err := isMatrixSummary(baselinePath)
+	if err != nil {
+		return err
+	}
+	candidateMatrix, err := isMatrixSummary(candidatePath)
+	if err != nil {
+		return err
+	}
+	if baselineMatrix != candidateMatrix {
+		return fmt.Errorf("cannot compare a matrix summary with a single benchmark result")
+	}
+
+	if baselineMatrix {
+		return compareMatrix(baselinePath, candidatePath, level)
+	}
+	return compareSingle(baselinePath, candidatePath, level)
+}
+
+// compareSingle compares two single benchmark results run by run
+func compareSingle(baselinePath, candidatePath string, level float64) error {
+	baselineLabel, baseline, err := benchmark.LoadDurations(baselinePath)
+	if err != nil {
+		return err
+	}
+	candidateLabel, candidate, err := benchmark.LoadDurations(candidatePath)
+	if err != nil {
+		return err
+	}
+
+	diff := benchmark.DiffDurations(baseline, candidate, level)
+	diff.Baseline.Name = fmt.Sprintf("%s (%s)", baselinePath, baselineLabel)
+	diff.Candidate.Name = fmt.Sprintf("%s (%s)", candidatePath, candidateLabel)
+
+	benchmark.PrintDiff(diff)
+
+	if compareJSON != "" {
+		if err := benchmark.SaveDiffJSON(diff, compareJSON); err != nil {
+			return fmt.Errorf("failed to save comparison JSON: %w", err)
+		}
+		fmt.Printf("\nComparison JSON saved to: %s\n", compareJSON)
+	}
+	if compareMarkdown != "" {
+		if err := benchmark.SaveDiffMarkdown(diff, compareMarkdown); err != nil {
+			return fmt.Errorf("failed to save comparison Markdown: %w", err)
+		}
+		fmt.Printf("Comparison report saved to: %s\n", compareMarkdown)
+	}
+	return nil
+}
+
+// compareMatrix compares two matrix summaries configuration by configuration
+func compareMatrix(baselinePath, candidatePath string, level float64) error {
+	baseline, err := matrix.LoadMatrixResult(baselinePath)
+	if err != nil {
+		return err
+	}
+	candidate, err := matrix.LoadMatrixResult(candidatePath)
+	if err != nil {
+		return err
+	}
+
+	diff := matrix.DiffMatrix(baseline, candidate, level)
+	diff.Baseline = baselinePath
+	diff.Candidate = candidatePath
+
+	matrix.PrintDiff(diff)
+
+	if compareJSON != "" {
+		if err := matrix.SaveDiffJSON(diff, compareJSON); err != nil {
+			return fmt.Errorf("failed to save comparison JSON: %w", err)
+		}
+		fmt.Printf("\nComparison JSON saved to: %s\n", compareJSON)
+	}
+	if compareMarkdown != "" {
+		if err := matrix.SaveDiffMarkdown(diff, compareMarkdown); err != nil {
+			return fmt.Errorf("failed to save comparison Markdown: %w", err)
+		}
+		fmt.Printf("Comparison report saved to: %s\n", compareMarkdown)
+	}
+	return nil
+}
+
+// isMatrixSummary reports whether a result file is a matrix summary, which
+// lists its configurations under "results", rather than a single benchmark
+// result, which lists its runs under "runs"
+func isMatrixSummary(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return false, fmt.Errorf("%s: not a caliper result: %w", path, err)
+	}
+	if _, ok := top["results"]; ok {
+		return true, nil
+	}
+	if _, ok := top["runs"]; ok {
+		return false, nil
+	}
+	return false, fmt.Errorf("%s: not a caliper result or matrix summary", path)
+}
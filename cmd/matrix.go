@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/attunehq/caliper/benchmark"
+	"github.com/attunehq/caliper/matrix"
 	"github.com/spf13/cobra"
 )
 
@@ -22,3 +27,193 @@ Available subcommands:
 func init() {
 	rootCmd.AddCommand(matrixCmd)
 }
+
+// matrixFlags holds the flags shared by all matrix subcommands. Each
+// subcommand registers its own set and adds the flags that select its
+// CPU:RAM configurations.
+type matrixFlags struct {
+	image             string
+	repo              string
+	command           string
+	runs              int
+	outputDir         string
+	name              string
+	noWarmup          bool
+	warmup            string
+	debug             bool
+	shell             string
+	calibrate         bool
+	env               []string
+	envFile           string
+	cleanEnv          bool
+	cwd               string
+	timeout           time.Duration
+	setup             string
+	prepare           string
+	conclude          string
+	cleanup           string
+	service           string
+	readyTCP          string
+	readyHTTP         string
+	readyOutput       string
+	readyTimeout      time.Duration
+	measureStartup    bool
+	expectedExitCodes []int
+	ignoreFailure     bool
+	retries           int
+	retryOnExitCodes  []int
+	minSuccessRate    float64
+	outlierMethod     string
+	trimOutliers      bool
+	confidence        string
+	percentiles       []float64
+	requireQuietHost  bool
+}
+
+// register adds the shared flags to a matrix subcommand
+func (f *matrixFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.image, "image", "", "Docker image to use (required)")
+	cmd.Flags().StringVar(&f.repo, "repo", "", "Git repository URL to clone (required)")
+	cmd.Flags().StringVarP(&f.command, "command", "c", "", "Command to benchmark (required)")
+	cmd.Flags().IntVarP(&f.runs, "runs", "n", 10, "Number of benchmark runs per configuration")
+	cmd.Flags().StringVar(&f.outputDir, "output-dir", "./matrix-results", "Directory to save output files")
+	cmd.Flags().StringVar(&f.name, "name", "", "Benchmark name for reports (default: timestamp)")
+	cmd.Flags().BoolVar(&f.noWarmup, "no-warmup", false, "Skip the warm-up run")
+	cmd.Flags().StringVar(&f.warmup, "warmup", "", "Number of warm-up runs, or 'auto' to warm up until durations stabilise (default: 1)")
+	cmd.Flags().BoolVar(&f.debug, "debug", false, "Enable debug logging with real-time output")
+	cmd.Flags().StringVar(&f.shell, "shell", "", "Shell inside the container: sh, bash, zsh, a path, or 'none' to run the command directly (default: bash)")
+	cmd.Flags().BoolVar(&f.calibrate, "calibrate", false, "Subtract the shell's spawn time from the statistics")
+	cmd.Flags().StringArrayVar(&f.env, "env", nil, "Set an environment variable for the command and hooks: --env KEY=VALUE (repeatable)")
+	cmd.Flags().StringVar(&f.envFile, "env-file", "", "Read environment variables from a local file of KEY=VALUE lines (--env takes precedence)")
+	cmd.Flags().BoolVar(&f.cleanEnv, "clean-env", false, "Start the command from a minimal environment instead of the container's")
+	cmd.Flags().StringVar(&f.cwd, "cwd", "", "Working directory for the command and hooks, relative to the repository checkout")
+	cmd.Flags().StringVar(&f.setup, "setup", "", "Command to run once before all runs in each configuration (untimed)")
+	cmd.Flags().StringVar(&f.prepare, "prepare", "", "Command to run before every run, including warm-up (untimed)")
+	cmd.Flags().StringVar(&f.conclude, "conclude", "", "Command to run after every run, including warm-up (untimed)")
+	cmd.Flags().StringVar(&f.cleanup, "cleanup", "", "Command to run once after all runs in each configuration (untimed)")
+	cmd.Flags().StringVar(&f.service, "service", "", "Command started in the background before the runs in each configuration and stopped after them")
+	cmd.Flags().StringVar(&f.readyTCP, "ready-tcp", "", "Wait until this host:port inside the container accepts connections before the runs")
+	cmd.Flags().StringVar(&f.readyHTTP, "ready-http", "", "Wait until this URL inside the container answers 200 OK before the runs")
+	cmd.Flags().StringVar(&f.readyOutput, "ready-output", "", "Wait until a line of the service's output matches this regular expression")
+	cmd.Flags().DurationVar(&f.readyTimeout, "ready-timeout", benchmark.DefaultReadyTimeout, "How long the service may take to become ready")
+	cmd.Flags().BoolVar(&f.measureStartup, "measure-startup", false, "Time the command from its start until the readiness probe passes, then stop it")
+	cmd.Flags().DurationVar(&f.timeout, "timeout", 0, "Per-run timeout inside each configuration (e.g. '30m') (default: no timeout)")
+	cmd.Flags().IntSliceVar(&f.expectedExitCodes, "expected-exit-code", nil, "Exit code that counts as success (repeatable, default: 0)")
+	cmd.Flags().BoolVar(&f.ignoreFailure, "ignore-failure", false, "Time every run and include it in the statistics, whatever its exit code")
+	cmd.Flags().IntVar(&f.retries, "retries", 0, "Retry a failed run up to N times inside each configuration; only the final attempt is timed")
+	cmd.Flags().IntSliceVar(&f.retryOnExitCodes, "retry-on-exit-code", nil, "Only retry runs that exited with this code (repeatable, default: any failure)")
+	cmd.Flags().Float64Var(&f.minSuccessRate, "min-success-rate", 100, "Minimum percentage of successful runs for a configuration to count as successful")
+	cmd.Flags().StringVar(&f.outlierMethod, "outlier-method", "", "How outlier runs are detected inside each configuration: iqr, mad or none (default: iqr)")
+	cmd.Flags().BoolVar(&f.trimOutliers, "trim-outliers", false, "Exclude outlier runs from the statistics of each configuration")
+	cmd.Flags().StringVar(&f.confidence, "confidence", "", "Confidence level of the intervals of the mean and median (e.g. '99%') (default: 95%)")
+	cmd.Flags().Float64SliceVar(&f.percentiles, "percentiles", nil, "Percentiles to report in addition to P90 and P95 (e.g. '50,75,99')")
+	cmd.Flags().BoolVar(&f.requireQuietHost, "require-quiet-host", false, "Before each run, wait up to 5 minutes for background load in the container to settle")
+
+	cmd.MarkFlagRequired("image")
+	cmd.MarkFlagRequired("repo")
+	cmd.MarkFlagRequired("command")
+}
+
+// config builds the configuration of a matrix benchmark from the shared flags.
+// The subcommand fills in its type-specific fields (FixedCPU, CPUList, ...).
+func (f *matrixFlags) config(benchmarkType matrix.BenchmarkType, configs []matrix.ResourceConfig) (matrix.Config, error) {
+	// Generate benchmark name if not provided
+	benchmarkName := f.name
+	if benchmarkName == "" {
+		benchmarkName = fmt.Sprintf("%s_%s", benchmarkType, time.Now().Format("20060102_150405"))
+	}
+
+	env, err := commandEnv(f.envFile, f.env)
+	if err != nil {
+		return matrix.Config{}, err
+	}
+
+	config := matrix.Config{
+		Image:             f.image,
+		RepoURL:           f.repo,
+		Command:           f.command,
+		Runs:              f.runs,
+		OutputDir:         f.outputDir,
+		Name:              benchmarkName,
+		Configs:           configs,
+		SkipWarmup:        f.noWarmup,
+		Warmup:            f.warmup,
+		Debug:             f.debug,
+		Shell:             f.shell,
+		Calibrate:         f.calibrate,
+		Env:               env,
+		CleanEnv:          f.cleanEnv,
+		Cwd:               f.cwd,
+		Timeout:           f.timeout,
+		Setup:             f.setup,
+		Prepare:           f.prepare,
+		Conclude:          f.conclude,
+		Cleanup:           f.cleanup,
+		Service:           f.service,
+		Readiness:         readinessProbe(f.readyTCP, f.readyHTTP, f.readyOutput, f.readyTimeout),
+		MeasureStartup:    f.measureStartup,
+		ExpectedExitCodes: f.expectedExitCodes,
+		IgnoreFailure:     f.ignoreFailure,
+		Retries:           f.retries,
+		RetryOnExitCodes:  f.retryOnExitCodes,
+		MinSuccessRate:    f.minSuccessRate,
+		OutlierMethod:     f.outlierMethod,
+		TrimOutliers:      f.trimOutliers,
+		Confidence:        f.confidence,
+		Percentiles:       f.percentiles,
+		RequireQuietHost:  f.requireQuietHost,
+		Type:              benchmarkType,
+	}
+	return config, validateMatrixConfig(config)
+}
+
+// validateMatrixConfig checks the options forwarded to the runs inside the
+// containers, before any container is started
+func validateMatrixConfig(config matrix.Config) error {
+	if config.Calibrate && config.Shell == benchmark.ShellNone {
+		return fmt.Errorf("--calibrate cannot be used with --shell=none (there is no shell to calibrate)")
+	}
+	if config.MinSuccessRate < 0 || config.MinSuccessRate > 100 {
+		return fmt.Errorf("--min-success-rate must be between 0 and 100")
+	}
+	for _, code := range config.ExpectedExitCodes {
+		if code < 0 || code > 255 {
+			return fmt.Errorf("--expected-exit-code must be between 0 and 255, got %d", code)
+		}
+	}
+	if config.Retries < 0 {
+		return fmt.Errorf("--retries must not be negative")
+	}
+	if len(config.RetryOnExitCodes) > 0 && config.Retries == 0 {
+		return fmt.Errorf("--retry-on-exit-code requires --retries")
+	}
+	if err := validateService(config.Service, config.Readiness, config.MeasureStartup); err != nil {
+		return err
+	}
+	if config.MeasureStartup && config.Timeout > 0 {
+		return fmt.Errorf("--timeout cannot be used with --measure-startup (use --ready-timeout)")
+	}
+	if config.Warmup != "" {
+		if _, _, err := benchmark.ParseWarmup(config.Warmup); err != nil {
+			return err
+		}
+	}
+	if config.OutlierMethod != "" {
+		method, err := benchmark.ParseOutlierMethod(config.OutlierMethod)
+		if err != nil {
+			return err
+		}
+		if config.TrimOutliers && method == benchmark.OutlierNone {
+			return fmt.Errorf("--trim-outliers cannot be used with --outlier-method none")
+		}
+	}
+	if config.Confidence != "" {
+		if _, err := benchmark.ParseConfidence(config.Confidence); err != nil {
+			return err
+		}
+	}
+	if err := benchmark.ValidatePercentiles(config.Percentiles); err != nil {
+		return fmt.Errorf("--percentiles: %w", err)
+	}
+	return nil
+}
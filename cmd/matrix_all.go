@@ -2,23 +2,15 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/attunehq/caliper/matrix"
 	"github.com/spf13/cobra"
 )
 
 var (
-	allImage     string
-	allRepo      string
-	allCommand   string
-	allRuns      int
-	allCpus      string
-	allRams      string
-	allOutputDir string
-	allName      string
-	allNoWarmup  bool
-	allDebug     bool
+	allFlags matrixFlags
+	allCpus  string
+	allRams  string
 )
 
 var allCmd = &cobra.Command{
@@ -45,20 +37,10 @@ This will test 16 configurations (4 CPUs x 4 RAMs) and generate:
 }
 
 func init() {
-	allCmd.Flags().StringVar(&allImage, "image", "", "Docker image to use (required)")
-	allCmd.Flags().StringVar(&allRepo, "repo", "", "Git repository URL to clone (required)")
-	allCmd.Flags().StringVarP(&allCommand, "command", "c", "", "Command to benchmark (required)")
-	allCmd.Flags().IntVarP(&allRuns, "runs", "n", 10, "Number of benchmark runs per configuration")
+	allFlags.register(allCmd)
 	allCmd.Flags().StringVar(&allCpus, "cpus", "", "CPU values to test (e.g., '2,4,8,16') (required)")
 	allCmd.Flags().StringVar(&allRams, "rams", "", "RAM values in GB to test (e.g., '8,16,32,64') (required)")
-	allCmd.Flags().StringVar(&allOutputDir, "output-dir", "./matrix-results", "Directory to save output files")
-	allCmd.Flags().StringVar(&allName, "name", "", "Benchmark name for reports (default: timestamp)")
-	allCmd.Flags().BoolVar(&allNoWarmup, "no-warmup", false, "Skip the warm-up run")
-	allCmd.Flags().BoolVar(&allDebug, "debug", false, "Enable debug logging with real-time output")
 
-	allCmd.MarkFlagRequired("image")
-	allCmd.MarkFlagRequired("repo")
-	allCmd.MarkFlagRequired("command")
 	allCmd.MarkFlagRequired("cpus")
 	allCmd.MarkFlagRequired("rams")
 
@@ -81,27 +63,12 @@ func runAll(cmd *cobra.Command, args []string) error {
 	// Generate full grid configurations (CPU first, then RAM)
 	resourceConfigs := matrix.GenerateGridConfigs(cpuList, ramList)
 
-	// Generate benchmark name if not provided
-	benchmarkName := allName
-	if benchmarkName == "" {
-		benchmarkName = fmt.Sprintf("all_%s", time.Now().Format("20060102_150405"))
-	}
-
-	// Create matrix configuration
-	config := matrix.Config{
-		Image:      allImage,
-		RepoURL:    allRepo,
-		Command:    allCommand,
-		Runs:       allRuns,
-		OutputDir:  allOutputDir,
-		Name:       benchmarkName,
-		Configs:    resourceConfigs,
-		SkipWarmup: allNoWarmup,
-		Debug:      allDebug,
-		Type:       matrix.BenchmarkTypeAll,
-		CPUList:    cpuList,
-		RAMList:    ramList,
+	config, err := allFlags.config(matrix.BenchmarkTypeAll, resourceConfigs)
+	if err != nil {
+		return err
 	}
+	config.CPUList = cpuList
+	config.RAMList = ramList
 
 	return runMatrixBenchmark(config)
 }
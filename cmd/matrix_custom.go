@@ -7,22 +7,14 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
-	"time"
 
 	"github.com/attunehq/caliper/matrix"
 	"github.com/spf13/cobra"
 )
 
 var (
-	customImage     string
-	customRepo      string
-	customCommand   string
-	customRuns      int
-	customConfigs   string
-	customOutputDir string
-	customName      string
-	customNoWarmup  bool
-	customDebug     bool
+	customFlags   matrixFlags
+	customConfigs string
 )
 
 var customCmd = &cobra.Command{
@@ -42,19 +34,9 @@ control over which configurations to benchmark.`,
 }
 
 func init() {
-	customCmd.Flags().StringVar(&customImage, "image", "", "Docker image to use (required)")
-	customCmd.Flags().StringVar(&customRepo, "repo", "", "Git repository URL to clone (required)")
-	customCmd.Flags().StringVarP(&customCommand, "command", "c", "", "Command to benchmark (required)")
-	customCmd.Flags().IntVarP(&customRuns, "runs", "n", 10, "Number of benchmark runs per configuration")
+	customFlags.register(customCmd)
 	customCmd.Flags().StringVar(&customConfigs, "configs", "", "CPU:RAM configurations (e.g., '2:8,4:16,8:32') (required)")
-	customCmd.Flags().StringVar(&customOutputDir, "output-dir", "./matrix-results", "Directory to save output files")
-	customCmd.Flags().StringVar(&customName, "name", "", "Benchmark name for reports (default: timestamp)")
-	customCmd.Flags().BoolVar(&customNoWarmup, "no-warmup", false, "Skip the warm-up run")
-	customCmd.Flags().BoolVar(&customDebug, "debug", false, "Enable debug logging with real-time output")
-
-	customCmd.MarkFlagRequired("image")
-	customCmd.MarkFlagRequired("repo")
-	customCmd.MarkFlagRequired("command")
+
 	customCmd.MarkFlagRequired("configs")
 
 	matrixCmd.AddCommand(customCmd)
@@ -67,24 +49,9 @@ func runCustom(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error parsing configs: %w", err)
 	}
 
-	// Generate benchmark name if not provided
-	benchmarkName := customName
-	if benchmarkName == "" {
-		benchmarkName = fmt.Sprintf("custom_%s", time.Now().Format("20060102_150405"))
-	}
-
-	// Create matrix configuration
-	config := matrix.Config{
-		Image:      customImage,
-		RepoURL:    customRepo,
-		Command:    customCommand,
-		Runs:       customRuns,
-		OutputDir:  customOutputDir,
-		Name:       benchmarkName,
-		Configs:    resourceConfigs,
-		SkipWarmup: customNoWarmup,
-		Debug:      customDebug,
-		Type:       matrix.BenchmarkTypeCustom,
+	config, err := customFlags.config(matrix.BenchmarkTypeCustom, resourceConfigs)
+	if err != nil {
+		return err
 	}
 
 	return runMatrixBenchmark(config)
@@ -2,23 +2,15 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/attunehq/caliper/matrix"
 	"github.com/spf13/cobra"
 )
 
 var (
-	sweepCPUImage     string
-	sweepCPURepo      string
-	sweepCPUCommand   string
-	sweepCPURuns      int
-	sweepCPUCpus      string
-	sweepCPURam       int
-	sweepCPUOutputDir string
-	sweepCPUName      string
-	sweepCPUNoWarmup  bool
-	sweepCPUDebug     bool
+	sweepCPUFlags matrixFlags
+	sweepCPUCpus  string
+	sweepCPURam   int
 )
 
 var sweepCPUCmd = &cobra.Command{
@@ -39,20 +31,10 @@ for a given memory allocation.`,
 }
 
 func init() {
-	sweepCPUCmd.Flags().StringVar(&sweepCPUImage, "image", "", "Docker image to use (required)")
-	sweepCPUCmd.Flags().StringVar(&sweepCPURepo, "repo", "", "Git repository URL to clone (required)")
-	sweepCPUCmd.Flags().StringVarP(&sweepCPUCommand, "command", "c", "", "Command to benchmark (required)")
-	sweepCPUCmd.Flags().IntVarP(&sweepCPURuns, "runs", "n", 10, "Number of benchmark runs per configuration")
+	sweepCPUFlags.register(sweepCPUCmd)
 	sweepCPUCmd.Flags().StringVar(&sweepCPUCpus, "cpus", "", "CPU values to test (e.g., '2,4,8,16') (required)")
 	sweepCPUCmd.Flags().IntVar(&sweepCPURam, "ram", 0, "Fixed RAM in GB (required)")
-	sweepCPUCmd.Flags().StringVar(&sweepCPUOutputDir, "output-dir", "./matrix-results", "Directory to save output files")
-	sweepCPUCmd.Flags().StringVar(&sweepCPUName, "name", "", "Benchmark name for reports (default: timestamp)")
-	sweepCPUCmd.Flags().BoolVar(&sweepCPUNoWarmup, "no-warmup", false, "Skip the warm-up run")
-	sweepCPUCmd.Flags().BoolVar(&sweepCPUDebug, "debug", false, "Enable debug logging with real-time output")
 
-	sweepCPUCmd.MarkFlagRequired("image")
-	sweepCPUCmd.MarkFlagRequired("repo")
-	sweepCPUCmd.MarkFlagRequired("command")
 	sweepCPUCmd.MarkFlagRequired("cpus")
 	sweepCPUCmd.MarkFlagRequired("ram")
 
@@ -74,27 +56,12 @@ func runSweepCPU(cmd *cobra.Command, args []string) error {
 	// Generate configurations
 	resourceConfigs := matrix.GenerateSweepCPUConfigs(cpuList, sweepCPURam)
 
-	// Generate benchmark name if not provided
-	benchmarkName := sweepCPUName
-	if benchmarkName == "" {
-		benchmarkName = fmt.Sprintf("sweep-cpu_%s", time.Now().Format("20060102_150405"))
-	}
-
-	// Create matrix configuration
-	config := matrix.Config{
-		Image:      sweepCPUImage,
-		RepoURL:    sweepCPURepo,
-		Command:    sweepCPUCommand,
-		Runs:       sweepCPURuns,
-		OutputDir:  sweepCPUOutputDir,
-		Name:       benchmarkName,
-		Configs:    resourceConfigs,
-		SkipWarmup: sweepCPUNoWarmup,
-		Debug:      sweepCPUDebug,
-		Type:       matrix.BenchmarkTypeSweepCPU,
-		FixedRAM:   sweepCPURam,
-		CPUList:    cpuList,
+	config, err := sweepCPUFlags.config(matrix.BenchmarkTypeSweepCPU, resourceConfigs)
+	if err != nil {
+		return err
 	}
+	config.FixedRAM = sweepCPURam
+	config.CPUList = cpuList
 
 	return runMatrixBenchmark(config)
 }
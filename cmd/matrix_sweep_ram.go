@@ -2,23 +2,15 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/attunehq/caliper/matrix"
 	"github.com/spf13/cobra"
 )
 
 var (
-	sweepRAMImage     string
-	sweepRAMRepo      string
-	sweepRAMCommand   string
-	sweepRAMRuns      int
-	sweepRAMRams      string
-	sweepRAMCpu       int
-	sweepRAMOutputDir string
-	sweepRAMName      string
-	sweepRAMNoWarmup  bool
-	sweepRAMDebug     bool
+	sweepRAMFlags matrixFlags
+	sweepRAMRams  string
+	sweepRAMCpu   int
 )
 
 var sweepRAMCmd = &cobra.Command{
@@ -39,20 +31,10 @@ for a given CPU allocation.`,
 }
 
 func init() {
-	sweepRAMCmd.Flags().StringVar(&sweepRAMImage, "image", "", "Docker image to use (required)")
-	sweepRAMCmd.Flags().StringVar(&sweepRAMRepo, "repo", "", "Git repository URL to clone (required)")
-	sweepRAMCmd.Flags().StringVarP(&sweepRAMCommand, "command", "c", "", "Command to benchmark (required)")
-	sweepRAMCmd.Flags().IntVarP(&sweepRAMRuns, "runs", "n", 10, "Number of benchmark runs per configuration")
+	sweepRAMFlags.register(sweepRAMCmd)
 	sweepRAMCmd.Flags().StringVar(&sweepRAMRams, "rams", "", "RAM values in GB to test (e.g., '8,16,32,64') (required)")
 	sweepRAMCmd.Flags().IntVar(&sweepRAMCpu, "cpu", 0, "Fixed CPU count (required)")
-	sweepRAMCmd.Flags().StringVar(&sweepRAMOutputDir, "output-dir", "./matrix-results", "Directory to save output files")
-	sweepRAMCmd.Flags().StringVar(&sweepRAMName, "name", "", "Benchmark name for reports (default: timestamp)")
-	sweepRAMCmd.Flags().BoolVar(&sweepRAMNoWarmup, "no-warmup", false, "Skip the warm-up run")
-	sweepRAMCmd.Flags().BoolVar(&sweepRAMDebug, "debug", false, "Enable debug logging with real-time output")
 
-	sweepRAMCmd.MarkFlagRequired("image")
-	sweepRAMCmd.MarkFlagRequired("repo")
-	sweepRAMCmd.MarkFlagRequired("command")
 	sweepRAMCmd.MarkFlagRequired("rams")
 	sweepRAMCmd.MarkFlagRequired("cpu")
 
@@ -74,27 +56,12 @@ func runSweepRAM(cmd *cobra.Command, args []string) error {
 	// Generate configurations
 	resourceConfigs := matrix.GenerateSweepRAMConfigs(ramList, sweepRAMCpu)
 
-	// Generate benchmark name if not provided
-	benchmarkName := sweepRAMName
-	if benchmarkName == "" {
-		benchmarkName = fmt.Sprintf("sweep-ram_%s", time.Now().Format("20060102_150405"))
-	}
-
-	// Create matrix configuration
-	config := matrix.Config{
-		Image:      sweepRAMImage,
-		RepoURL:    sweepRAMRepo,
-		Command:    sweepRAMCommand,
-		Runs:       sweepRAMRuns,
-		OutputDir:  sweepRAMOutputDir,
-		Name:       benchmarkName,
-		Configs:    resourceConfigs,
-		SkipWarmup: sweepRAMNoWarmup,
-		Debug:      sweepRAMDebug,
-		Type:       matrix.BenchmarkTypeSweepRAM,
-		FixedCPU:   sweepRAMCpu,
-		RAMList:    ramList,
+	config, err := sweepRAMFlags.config(matrix.BenchmarkTypeSweepRAM, resourceConfigs)
+	if err != nil {
+		return err
 	}
+	config.FixedCPU = sweepRAMCpu
+	config.RAMList = ramList
 
 	return runMatrixBenchmark(config)
 }
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/attunehq/caliper/benchmark"
+	"github.com/attunehq/caliper/matrix"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for report command
+	reportFormat string
+	reportOutput string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report <result.json>",
+	Short: "Render a saved result again as console output, Markdown, CSV or HTML",
+	Long: `Render a saved benchmark result again, without re-running the benchmark.
+
+The file is a JSON output of caliper: a single benchmark result or a matrix
+summary (*_summary.json), of any supported schema version. It is rendered by
+the same code as at the end of a run:
+
+  console  print the summary to the terminal
+  md       Markdown report
+  csv      CSV of the runs (single) or configurations (matrix)
+  html     self-contained HTML report with charts
+
+The md, csv and html reports are saved next to the JSON file as
+<name>.report.<format>, unless --output is given, so that they never replace
+the reports written by the run itself (<name>.md, <name>.csv).`,
+	Example: `  caliper report results/main.json
+  caliper report results/main.json --format html
+  caliper report matrix-results/influxdb_all_summary.json --format md --output report.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", "console", "Output format: console, md, csv or html")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "File to save the md, csv or html report to (default: <name>.report.<format> next to the result)")
+
+	rootCmd.AddCommand(reportCmd)
+}
+
+// reportNames names the saved report formats in the confirmation message
+var reportNames = map[string]string{
+	"md":   "Markdown report",
+	"csv":  "CSV output",
+	"html": "HTML report",
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	format := strings.ToLower(reportFormat)
+	if _, ok := reportNames[format]; !ok && format != "console" {
+		return fmt.Errorf("unknown format %q (use console, md, csv or html)", reportFormat)
+	}
+
+	isMatrix, err := isMatrixSummary(path)
+	if err != nil {
+		return err
+	}
+
+	// The run saved its own <name>.md and <name>.csv next to the result
+	output := reportOutput
+	if output == "" {
+		output = strings.TrimSuffix(path, filepath.Ext(path)) + ".report." + format
+	}
+	if format != "console" && filepath.Clean(output) == filepath.Clean(path) {
+		return fmt.Errorf("--output %s would replace the result being reported", output)
+	}
+
+	if isMatrix {
+		err = reportMatrix(path, format, output)
+	} else {
+		err = reportSingle(path, format, output)
+	}
+	if err != nil {
+		return err
+	}
+
+	if format != "console" {
+		fmt.Printf("%s saved to: %s\n", reportNames[format], output)
+	}
+	return nil
+}
+
+// reportSingle renders a single benchmark result
+func reportSingle(path, format, output string) error {
+	result, err := benchmark.LoadResult(path)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "console":
+		benchmark.PrintConsole(result)
+		return nil
+	case "md":
+		err = benchmark.SaveMarkdown(result, output)
+	case "csv":
+		err = benchmark.SaveCSV(result, output)
+	case "html":
+		err = benchmark.SaveHTML(result, output)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", reportNames[format], err)
+	}
+	return nil
+}
+
+// reportMatrix renders a matrix summary
+func reportMatrix(path, format, output string) error {
+	result, err := matrix.LoadMatrixResult(path)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "console":
+		matrix.PrintSummaryTable(result)
+		if result.Config.Type == matrix.BenchmarkTypeAll {
+			matrix.PrintAllGraphs(result)
+		} else {
+			matrix.PrintBuildTimeGraph(result)
+		}
+		return nil
+	case "md":
+		err = matrix.SaveSummaryMarkdown(result, output)
+	case "csv":
+		err = matrix.SaveSummaryCSV(result, output)
+	case "html":
+		err = matrix.SaveSummaryHTML(result, output)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", reportNames[format], err)
+	}
+	return nil
+}
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/attunehq/caliper/benchmark"
@@ -15,12 +21,72 @@ var (
 	Version = "dev"
 
 	// Flags for root command (single benchmark)
-	runs      int
-	command   string
-	outputDir string
-	name      string
-	noWarmup  bool
-	debug     bool
+	runs         int
+	commands     []string
+	commandNames []string
+	outputDir    string
+	name         string
+	noWarmup     bool
+	warmup       string
+	warmupTol    float64
+	maxWarmup    int
+	noLogs       bool
+	shell        string
+	calibrate    bool
+	maxLogSize   int
+	stderrLines  int
+	noCheckpoint bool
+	resume       bool
+
+	// Flags for the command environment
+	envVars  []string
+	envFile  string
+	cleanEnv bool
+	cwd      string
+
+	// Flags for the failure policy
+	expectedExitCodes []int
+	ignoreFailure     bool
+	minSuccessRate    float64
+	retries           int
+	retryOnExitCodes  []int
+	debug             bool
+	timeout           time.Duration
+	setup             string
+	prepare           string
+	conclude          string
+	cleanup           string
+
+	// Flags for the service under test
+	service        string
+	readyTCP       string
+	readyHTTP      string
+	readyOutput    string
+	readyTimeout   time.Duration
+	measureStartup bool
+
+	// Flags for outlier handling
+	outlierMethod string
+	trimOutliers  bool
+
+	// Flags for the reported statistics
+	confidence  string
+	percentiles []float64
+
+	// Flags for parameter scans
+	parameterScan string
+	parameterList string
+	parameterStep float64
+
+	// Flags for the adaptive run count
+	targetCI    string
+	ciStatistic string
+	minRuns     int
+	maxRuns     int
+	maxTime     time.Duration
+
+	// Flags for host noise
+	requireQuietHost bool
 )
 
 var rootCmd = &cobra.Command{
@@ -32,10 +98,32 @@ and provides detailed statistics about execution time, memory usage, and more.
 Run a single benchmark:
   caliper -n 10 -c "make build"
 
+Run until the 95% confidence interval of the mean is within ±2%:
+  caliper --target-ci 2% --max-time 30m -c "make build"
+
+Compare several commands:
+  caliper -n 10 -c "cargo build" -c "cargo build -Zthreads=8"
+
+Test whether a change made a command faster or slower:
+  caliper compare results/main.json results/feature.json
+
+Render a saved result again, e.g. as HTML:
+  caliper report results/main.json --format html
+
+Check a saved result against the published JSON Schema:
+  caliper validate results/main.json
+
+Scan a parameter substituted into the command:
+  caliper -n 5 -c "make -j{N}" --parameter-scan N=1..16 --step 2
+  caliper -n 5 -c "make -j{N}" --parameter-list N=1,2,4,8
+  caliper -n 5 -c "./bench --offset {D}" --parameter-scan D=-5..5
+
 Run benchmarks across multiple CPU/RAM configurations:
   caliper matrix --image ubuntu:22.04 --repo https://github.com/user/repo --configs "2:8,4:16"`,
 	Version: Version,
-	RunE:    runBenchmark,
+	// Positional arguments are only used by --parameter-scan/--parameter-list
+	Args: cobra.ArbitraryArgs,
+	RunE: runBenchmark,
 }
 
 // Execute runs the root command
@@ -53,69 +141,552 @@ func SetVersion(v string) {
 
 func init() {
 	rootCmd.Flags().IntVarP(&runs, "runs", "n", 0, "Number of times to run the benchmark (required)")
-	rootCmd.Flags().StringVarP(&command, "command", "c", "", "Command to benchmark (required)")
+	rootCmd.Flags().StringArrayVarP(&commands, "command", "c", nil, "Command to benchmark (required, repeat to compare several commands)")
+	rootCmd.Flags().StringArrayVar(&commandNames, "command-name", nil, "Display name for the corresponding --command (repeatable)")
 	rootCmd.Flags().StringVar(&outputDir, "output-dir", ".", "Directory to save output files")
 	rootCmd.Flags().StringVar(&name, "name", "", "Benchmark name for reports (default: timestamp)")
-	rootCmd.Flags().BoolVar(&noWarmup, "no-warmup", false, "Skip the warm-up run")
+	rootCmd.Flags().BoolVar(&noWarmup, "no-warmup", false, "Skip the warm-up run (same as --warmup 0)")
+	rootCmd.Flags().StringVar(&warmup, "warmup", "1", "Number of warm-up runs, or 'auto' to warm up until consecutive durations stabilise")
+	rootCmd.Flags().Float64Var(&warmupTol, "warmup-tolerance", benchmark.DefaultWarmupTolerance, "Relative difference between consecutive warm-up runs considered stable with --warmup auto")
+	rootCmd.Flags().IntVar(&maxWarmup, "max-warmup-runs", benchmark.DefaultMaxWarmupRuns, "Maximum number of warm-up runs with --warmup auto")
 	rootCmd.Flags().BoolVar(&debug, "debug", false, "Enable debug logging with real-time command output")
+	rootCmd.Flags().StringVar(&shell, "shell", benchmark.DefaultShell, "Shell used to run the command: sh, bash, zsh, a path, or 'none' to execute it directly without a shell")
+	rootCmd.Flags().BoolVar(&calibrate, "calibrate", false, "Measure the shell's spawn time and subtract it from the reported statistics")
+	rootCmd.Flags().StringArrayVar(&envVars, "env", nil, "Set an environment variable for the command and hooks: --env KEY=VALUE (repeatable)")
+	rootCmd.Flags().StringVar(&envFile, "env-file", "", "Read environment variables from a file of KEY=VALUE lines (--env takes precedence)")
+	rootCmd.Flags().BoolVar(&cleanEnv, "clean-env", false, "Start from a minimal environment (PATH, HOME, USER, SHELL, TERM, locale, TMPDIR, TZ) instead of inheriting it")
+	rootCmd.Flags().StringVar(&cwd, "cwd", "", "Working directory for the command and hooks (default: current directory)")
+	rootCmd.Flags().BoolVar(&noCheckpoint, "no-checkpoint", false, "Do not record completed runs in a checkpoint file")
+	rootCmd.Flags().BoolVar(&resume, "resume", false, "Continue an interrupted benchmark from its checkpoint (requires the same --name and --output-dir)")
+	rootCmd.Flags().BoolVar(&noLogs, "no-logs", false, "Do not write per-run stdout/stderr log files")
+	rootCmd.Flags().IntVar(&maxLogSize, "max-log-size", benchmark.DefaultLogSizeLimit>>20, "Maximum size of each per-run log file in MiB")
+	rootCmd.Flags().IntVar(&stderrLines, "stderr-lines", benchmark.DefaultStderrTailLines, "Number of trailing stderr lines recorded for each run")
+	rootCmd.Flags().StringVar(&setup, "setup", "", "Command to run once before all runs (untimed)")
+	rootCmd.Flags().StringVar(&prepare, "prepare", "", "Command to run before every run, including warm-up (untimed)")
+	rootCmd.Flags().StringVar(&conclude, "conclude", "", "Command to run after every run, including warm-up (untimed)")
+	rootCmd.Flags().StringVar(&cleanup, "cleanup", "", "Command to run once after all runs (untimed)")
+	rootCmd.Flags().StringVar(&service, "service", "", "Command started in the background before the runs (after --setup) and stopped after them (before --cleanup)")
+	rootCmd.Flags().StringVar(&readyTCP, "ready-tcp", "", "Wait until this host:port accepts connections before the runs (e.g. 'localhost:8080')")
+	rootCmd.Flags().StringVar(&readyHTTP, "ready-http", "", "Wait until this URL answers 200 OK before the runs (e.g. 'http://localhost:8080/health')")
+	rootCmd.Flags().StringVar(&readyOutput, "ready-output", "", "Wait until a line of the service's output matches this regular expression")
+	rootCmd.Flags().DurationVar(&readyTimeout, "ready-timeout", benchmark.DefaultReadyTimeout, "How long the service may take to become ready")
+	rootCmd.Flags().BoolVar(&measureStartup, "measure-startup", false, "Time the command from its start until the readiness probe passes, then stop it")
+	rootCmd.Flags().StringVar(&outlierMethod, "outlier-method", string(benchmark.OutlierIQR), "How outlier runs are detected: iqr (1.5×IQR fences), mad (modified z-score > 3.5) or none")
+	rootCmd.Flags().BoolVar(&trimOutliers, "trim-outliers", false, "Exclude outlier runs from the statistics (they are still reported)")
+	rootCmd.Flags().Float64SliceVar(&percentiles, "percentiles", nil, "Percentiles to report in addition to P90 and P95 (e.g. '50,75,99')")
+	rootCmd.Flags().StringVar(&confidence, "confidence", "95%", "Confidence level of the reported intervals of the mean and median, and of --target-ci (e.g. '99%' or '0.99')")
+	rootCmd.Flags().StringVar(&parameterScan, "parameter-scan", "", "Scan parameter NAME from MIN to MAX: --parameter-scan NAME=MIN..MAX (or NAME MIN MAX); {NAME} is replaced in the command, hooks, environment, --cwd, --service and readiness checks")
+	rootCmd.Flags().StringVar(&parameterList, "parameter-list", "", "Run once per value of parameter NAME: --parameter-list NAME=a,b,c (or NAME a,b,c)")
+	rootCmd.Flags().Float64Var(&parameterStep, "step", 1, "Step size for --parameter-scan")
+	rootCmd.Flags().StringVar(&targetCI, "target-ci", "", "Run until the relative confidence interval (see --confidence) is within this target (e.g. '2%' or '0.02'); replaces --runs")
+	rootCmd.Flags().StringVar(&ciStatistic, "ci-statistic", "mean", "Statistic whose confidence interval --target-ci targets: mean or median")
+	rootCmd.Flags().IntVar(&minRuns, "min-runs", benchmark.DefaultMinRuns, "Minimum number of runs with --target-ci")
+	rootCmd.Flags().IntVar(&maxRuns, "max-runs", benchmark.DefaultMaxRuns, "Maximum number of runs with --target-ci")
+	rootCmd.Flags().DurationVar(&maxTime, "max-time", 0, "Time budget for the measured runs with --target-ci (e.g. '30m', default: no budget)")
+	rootCmd.Flags().IntSliceVar(&expectedExitCodes, "expected-exit-code", nil, "Exit code that counts as success (repeatable, default: 0)")
+	rootCmd.Flags().BoolVar(&ignoreFailure, "ignore-failure", false, "Time every run and include it in the statistics, whatever its exit code")
+	rootCmd.Flags().IntVar(&retries, "retries", 0, "Retry a failed run up to N times; only the final attempt is timed")
+	rootCmd.Flags().IntSliceVar(&retryOnExitCodes, "retry-on-exit-code", nil, "Only retry runs that exited with this code (repeatable, default: any failure)")
+	rootCmd.Flags().Float64Var(&minSuccessRate, "min-success-rate", 100, "Minimum percentage of successful runs for a zero exit code")
+	rootCmd.Flags().BoolVar(&requireQuietHost, "require-quiet-host", false, "Before each run, wait up to 5 minutes for background load on the host to settle (Linux only)")
+	rootCmd.Flags().DurationVar(&timeout, "timeout", 0, "Per-run timeout (e.g. '30m'); the run's whole process group is killed when exceeded (default: no timeout)")
 }
 
 func runBenchmark(cmd *cobra.Command, args []string) error {
+	// Positional arguments carry the range or value list of a parameter scan
+	paramName, paramValues, err := parameterValues(args)
+	if err != nil {
+		return err
+	}
+
 	// If no flags provided, show help
-	if runs == 0 && command == "" {
+	if runs == 0 && targetCI == "" && len(commands) == 0 {
 		return cmd.Help()
 	}
 
 	// Validate required arguments
-	if runs <= 0 {
-		return fmt.Errorf("--runs/-n is required and must be greater than 0")
+	target, err := benchmark.ParseTargetCI(targetCI)
+	if err != nil {
+		return err
+	}
+
+	if target == 0 && runs <= 0 {
+		return fmt.Errorf("--runs/-n is required and must be greater than 0 (or use --target-ci)")
+	}
+
+	if target > 0 && cmd.Flags().Changed("runs") {
+		return fmt.Errorf("--runs and --target-ci cannot be used together")
+	}
+
+	statistic, err := benchmark.ParseCIStatistic(ciStatistic)
+	if err != nil {
+		return err
+	}
+
+	if minRuns < 2 {
+		return fmt.Errorf("--min-runs must be at least 2")
+	}
+
+	if maxRuns < minRuns {
+		return fmt.Errorf("--max-runs (%d) must not be less than --min-runs (%d)", maxRuns, minRuns)
+	}
+
+	if maxTime < 0 {
+		return fmt.Errorf("--max-time must not be negative")
+	}
+
+	warmupRuns, warmupAuto, err := benchmark.ParseWarmup(warmup)
+	if err != nil {
+		return err
+	}
+
+	if noWarmup && cmd.Flags().Changed("warmup") {
+		return fmt.Errorf("--no-warmup and --warmup cannot be used together")
+	}
+
+	if warmupTol <= 0 {
+		return fmt.Errorf("--warmup-tolerance must be greater than 0")
+	}
+
+	if maxWarmup < 2 {
+		return fmt.Errorf("--max-warmup-runs must be at least 2")
+	}
+
+	if shell == "" {
+		return fmt.Errorf("--shell cannot be empty")
+	}
+
+	if calibrate && shell == benchmark.ShellNone {
+		return fmt.Errorf("--calibrate cannot be used with --shell=none (there is no shell to calibrate)")
+	}
+
+	if minSuccessRate < 0 || minSuccessRate > 100 {
+		return fmt.Errorf("--min-success-rate must be between 0 and 100")
+	}
+
+	for _, code := range expectedExitCodes {
+		if code < 0 || code > 255 {
+			return fmt.Errorf("--expected-exit-code must be between 0 and 255, got %d", code)
+		}
+	}
+
+	if retries < 0 {
+		return fmt.Errorf("--retries must not be negative")
+	}
+
+	if len(retryOnExitCodes) > 0 && retries == 0 {
+		return fmt.Errorf("--retry-on-exit-code requires --retries")
+	}
+
+	outliers, err := benchmark.ParseOutlierMethod(outlierMethod)
+	if err != nil {
+		return err
+	}
+
+	if trimOutliers && outliers == benchmark.OutlierNone {
+		return fmt.Errorf("--trim-outliers cannot be used with --outlier-method none")
+	}
+
+	level, err := benchmark.ParseConfidence(confidence)
+	if err != nil {
+		return err
+	}
+
+	if err := benchmark.ValidatePercentiles(percentiles); err != nil {
+		return fmt.Errorf("--percentiles: %w", err)
+	}
+
+	if maxLogSize <= 0 {
+		return fmt.Errorf("--max-log-size must be greater than 0")
+	}
+
+	if stderrLines <= 0 {
+		return fmt.Errorf("--stderr-lines must be greater than 0")
+	}
+
+	env, err := commandEnv(envFile, envVars)
+	if err != nil {
+		return err
+	}
+
+	readiness := readinessProbe(readyTCP, readyHTTP, readyOutput, readyTimeout)
+	// A scanned parameter may stand for e.g. the port; the checks are
+	// validated as the first benchmark of the scan will run them
+	probe := readiness
+	if paramName != "" {
+		probe = benchmark.Config{Readiness: readiness}.WithParameter(benchmark.Parameter{Name: paramName, Value: paramValues[0]}).Readiness
+	}
+	if err := validateService(service, probe, measureStartup); err != nil {
+		return err
+	}
+
+	if measureStartup && timeout > 0 {
+		return fmt.Errorf("--timeout cannot be used with --measure-startup (use --ready-timeout)")
+	}
+
+	if resume && name == "" {
+		return fmt.Errorf("--resume requires the --name of the benchmark to continue")
 	}
 
-	if command == "" {
+	if resume && noCheckpoint {
+		return fmt.Errorf("--resume and --no-checkpoint cannot be used together")
+	}
+
+	skipWarmup := noWarmup || (warmupRuns == 0 && !warmupAuto)
+
+	if len(commands) == 0 {
 		return fmt.Errorf("--command/-c is required")
 	}
 
+	if len(commandNames) > len(commands) {
+		return fmt.Errorf("--command-name given %d times but only %d commands", len(commandNames), len(commands))
+	}
+
+	if timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+
 	// Generate benchmark name if not provided
 	benchmarkName := name
 	if benchmarkName == "" {
 		benchmarkName = fmt.Sprintf("benchmark_%s", time.Now().Format("20060102_150405"))
 	}
 
+	// Create benchmark configurations, grouped by command (one per parameter value when scanning)
+	groups := make([][]benchmark.Config, 0, len(commands))
+	unused := 0 // Commands whose benchmark doesn't use the scanned parameter
+	for i, c := range commands {
+		config := benchmark.Config{
+			Command:           c,
+			Runs:              runs,
+			Name:              benchmarkName,
+			OutputDir:         outputDir,
+			SkipWarmup:        skipWarmup,
+			Warmup:            warmupRuns,
+			WarmupAuto:        warmupAuto,
+			WarmupTolerance:   warmupTol,
+			MaxWarmupRuns:     maxWarmup,
+			Shell:             shell,
+			Calibrate:         calibrate,
+			Env:               env,
+			CleanEnv:          cleanEnv,
+			Dir:               cwd,
+			ExpectedExitCodes: expectedExitCodes,
+			IgnoreFailure:     ignoreFailure,
+			Retries:           retries,
+			RetryOnExitCodes:  retryOnExitCodes,
+			LogOutput:         !noLogs,
+			Checkpoint:        !noCheckpoint,
+			Resume:            resume,
+			LogSizeLimit:      int64(maxLogSize) << 20,
+			StderrTailLines:   stderrLines,
+			OutlierMethod:     outliers,
+			TrimOutliers:      trimOutliers,
+			Confidence:        level,
+			Percentiles:       percentiles,
+			Debug:             debug,
+			Timeout:           timeout,
+			Setup:             setup,
+			Prepare:           prepare,
+			Conclude:          conclude,
+			Cleanup:           cleanup,
+			Service:           service,
+			Readiness:         readiness,
+			MeasureStartup:    measureStartup,
+			RequireQuietHost:  requireQuietHost,
+		}
+		if target > 0 {
+			config.TargetCI = target
+			config.CIStatistic = statistic
+			config.MinRuns = minRuns
+			config.MaxRuns = maxRuns
+			config.MaxTime = maxTime
+		}
+		if i < len(commandNames) {
+			config.CommandName = commandNames[i]
+		}
+		// Keep the historical file names for a single command
+		if len(commands) > 1 {
+			config.Name = fmt.Sprintf("%s_%d", benchmarkName, i+1)
+		}
+
+		if paramName == "" {
+			groups = append(groups, []benchmark.Config{config})
+			continue
+		}
+		if !config.UsesParameter(paramName) {
+			unused++
+		}
+
+		variants := make([]benchmark.Config, 0, len(paramValues))
+		for _, value := range paramValues {
+			variant := config.WithParameter(benchmark.Parameter{Name: paramName, Value: value})
+			variant.Name = fmt.Sprintf("%s_%s-%s", config.Name, paramName, value)
+			variants = append(variants, variant)
+		}
+		groups = append(groups, variants)
+	}
+
+	if paramName != "" && unused == len(commands) {
+		return fmt.Errorf("the placeholder {%s} is not used in the command, hooks, environment, --cwd, --service or readiness checks", paramName)
+	}
+
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("error creating output directory: %w", err)
 	}
 
-	// Create benchmark configuration
-	config := benchmark.Config{
-		Command:    command,
-		Runs:       runs,
-		Name:       benchmarkName,
-		OutputDir:  outputDir,
-		SkipWarmup: noWarmup,
-		Debug:      debug,
+	// With --resume, the benchmarks after the interrupted one have no
+	// checkpoint yet and start afresh, but the first one must have one
+	if resume {
+		path := groups[0][0].CheckpointPath()
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no checkpoint to resume at %s", path)
+		}
 	}
 
 	fmt.Printf("Caliper\n")
 	fmt.Printf("=======\n")
-	fmt.Printf("Command: %s\n", config.Command)
-	if config.SkipWarmup {
-		fmt.Printf("Runs: %d (no warm-up)\n", config.Runs)
-	} else {
-		fmt.Printf("Runs: %d (+ 1 warm-up)\n", config.Runs)
+	for i, c := range commands {
+		if i < len(commandNames) {
+			fmt.Printf("Command: %s (%s)\n", commandNames[i], c)
+		} else {
+			fmt.Printf("Command: %s\n", c)
+		}
+	}
+	if paramName != "" {
+		fmt.Printf("Parameter: %s = %s\n", paramName, strings.Join(paramValues, ", "))
+	}
+	runsLabel := fmt.Sprintf("%d", runs)
+	if target > 0 {
+		runsLabel = fmt.Sprintf("until ±%s%% CI of the %s (%d-%d runs", strconv.FormatFloat(target*100, 'f', -1, 64), statistic, minRuns, maxRuns)
+		if maxTime > 0 {
+			runsLabel += fmt.Sprintf(", budget %s", maxTime)
+		}
+		runsLabel += ")"
+	}
+	switch {
+	case skipWarmup:
+		fmt.Printf("Runs: %s (no warm-up)\n", runsLabel)
+	case warmupAuto:
+		fmt.Printf("Runs: %s (+ warm-up until stable within %.0f%%, max %d)\n", runsLabel, warmupTol*100, maxWarmup)
+	default:
+		fmt.Printf("Runs: %s (+ %d warm-up)\n", runsLabel, warmupRuns)
+	}
+	if timeout > 0 {
+		fmt.Printf("Timeout: %s per run\n", timeout)
+	}
+	if shell != benchmark.DefaultShell {
+		fmt.Printf("Shell: %s\n", shell)
 	}
-	fmt.Printf("Output Directory: %s\n\n", config.OutputDir)
+	if len(expectedExitCodes) > 0 {
+		fmt.Printf("Expected Exit Codes: %v\n", expectedExitCodes)
+	}
+	if ignoreFailure {
+		fmt.Printf("Ignoring failures: every completed run is timed\n")
+	}
+	if retries > 0 {
+		fmt.Printf("Retries: up to %d per failed run\n", retries)
+	}
+	if requireQuietHost {
+		fmt.Printf("Waiting for a quiet host before each run\n")
+	}
+	if minSuccessRate < 100 {
+		fmt.Printf("Minimum Success Rate: %g%%\n", minSuccessRate)
+	}
+	if trimOutliers {
+		fmt.Printf("Outliers: excluded from the statistics (%s)\n", outliers.Description())
+	}
+	if level != benchmark.DefaultConfidence {
+		fmt.Printf("Confidence: %s\n", benchmark.FormatConfidence(level))
+	}
+	if prepare != "" {
+		fmt.Printf("Prepare: %s\n", prepare)
+	}
+	if service != "" {
+		fmt.Printf("Service: %s (ready: %s)\n", service, readiness.Description())
+	}
+	if measureStartup {
+		fmt.Printf("Measuring: startup until ready (%s, timeout %s)\n", readiness.Description(), readyTimeout)
+	}
+	fmt.Printf("Output Directory: %s\n\n", outputDir)
 
-	// Run the benchmark
-	result, err := benchmark.Run(config)
-	if err != nil {
-		return fmt.Errorf("error running benchmark: %w", err)
+	// Run each benchmark in turn
+	total := 0
+	for _, group := range groups {
+		total += len(group)
+	}
+
+	// The first interrupt stops the benchmark and reports the completed runs,
+	// a second one exits immediately
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 2)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		<-sigChan
+		fmt.Println("\nReceived interrupt signal, finishing with the completed runs (interrupt again to abort)...")
+		cancel()
+		<-sigChan
+		os.Exit(130)
+	}()
+
+	var results []*benchmark.Result
+	groupResults := make([][]*benchmark.Result, 0, len(groups))
+	exitCode := 0
+	for _, group := range groups {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var current []*benchmark.Result
+		for _, config := range group {
+			if ctx.Err() != nil {
+				break
+			}
+			if total > 1 {
+				fmt.Printf("━━━ Benchmark %d/%d: %s ━━━\n\n", len(results)+1, total, config.Label())
+			}
+
+			result, err := benchmark.Run(ctx, config, benchmark.WithReporter(benchmark.NewConsoleReporter(os.Stdout)))
+			if err != nil {
+				return fmt.Errorf("error running benchmark: %w", err)
+			}
+			results = append(results, result)
+			current = append(current, result)
+
+			// Display results to console
+			benchmark.PrintConsole(result)
+
+			// Save outputs
+			saveResult(result, outputDir, config.Name)
+
+			if !result.MeetsSuccessRate(minSuccessRate) {
+				exitCode = 1
+			}
+			if result.Interrupted {
+				exitCode = 130
+			}
+			fmt.Println()
+		}
+		groupResults = append(groupResults, current)
+	}
+
+	// Parameter scan summary, one per command
+	if paramName != "" {
+		for i, current := range groupResults {
+			scan := &benchmark.ScanResult{
+				Command:   commands[i],
+				Parameter: paramName,
+				Results:   current,
+			}
+			benchmark.PrintScan(scan)
+
+			scanName := benchmarkName
+			if len(commands) > 1 {
+				scanName = fmt.Sprintf("%s_%d", benchmarkName, i+1)
+			}
+
+			jsonPath := filepath.Join(outputDir, fmt.Sprintf("%s_scan.json", scanName))
+			if err := benchmark.SaveScanJSON(scan, jsonPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to save scan JSON: %v\n", err)
+			} else {
+				fmt.Printf("Scan JSON saved to: %s\n", jsonPath)
+			}
+
+			mdPath := filepath.Join(outputDir, fmt.Sprintf("%s_scan.md", scanName))
+			if err := benchmark.SaveScanMarkdown(scan, mdPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to save scan Markdown: %v\n", err)
+			} else {
+				fmt.Printf("Scan report saved to: %s\n", mdPath)
+			}
+		}
+	}
+
+	// Combined report when several commands were compared
+	if paramName == "" && len(results) > 1 {
+		comparison := benchmark.Compare(results)
+		benchmark.PrintComparison(comparison)
+
+		jsonPath := filepath.Join(outputDir, fmt.Sprintf("%s_comparison.json", benchmarkName))
+		if err := benchmark.SaveComparisonJSON(comparison, jsonPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save comparison JSON: %v\n", err)
+		} else {
+			fmt.Printf("\nComparison JSON saved to: %s\n", jsonPath)
+		}
+
+		mdPath := filepath.Join(outputDir, fmt.Sprintf("%s_comparison.md", benchmarkName))
+		if err := benchmark.SaveComparisonMarkdown(comparison, mdPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save comparison Markdown: %v\n", err)
+		} else {
+			fmt.Printf("Comparison report saved to: %s\n", mdPath)
+		}
+	}
+
+	// Exit with appropriate code
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+
+	return nil
+}
+
+// parameterValues resolves --parameter-scan/--parameter-list and their positional arguments
+// into the parameter name and the list of values to substitute
+func parameterValues(args []string) (string, []string, error) {
+	switch {
+	case parameterScan != "" && parameterList != "":
+		return "", nil, fmt.Errorf("--parameter-scan and --parameter-list cannot be combined")
+
+	case parameterScan != "":
+		// NAME=MIN..MAX keeps negative bounds inside the flag value, where
+		// they cannot be mistaken for flags
+		name, scanRange, inline := strings.Cut(parameterScan, "=")
+		if !inline {
+			if len(args) != 2 {
+				return "", nil, fmt.Errorf("--parameter-scan expects NAME=MIN..MAX or NAME MIN MAX (e.g. --parameter-scan N=1..16)")
+			}
+			scanRange = args[0] + ".." + args[1]
+		} else if len(args) > 0 {
+			return "", nil, fmt.Errorf("unexpected arguments after --parameter-scan %s: %s", parameterScan, strings.Join(args, " "))
+		}
+		min, max, err := benchmark.ParseScanRange(scanRange)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid --parameter-scan range: %w", err)
+		}
+		values, err := benchmark.ScanValues(min, max, parameterStep)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid --parameter-scan range: %w", err)
+		}
+		return name, values, nil
+
+	case parameterList != "":
+		name, list, inline := strings.Cut(parameterList, "=")
+		if !inline {
+			if len(args) != 1 {
+				return "", nil, fmt.Errorf("--parameter-list expects NAME=VALUES or NAME VALUES (e.g. --parameter-list N=1,2,4,8)")
+			}
+			list = args[0]
+		} else if len(args) > 0 {
+			return "", nil, fmt.Errorf("unexpected arguments after --parameter-list %s: %s", parameterList, strings.Join(args, " "))
+		}
+		values, err := benchmark.ParseParameterList(list)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid --parameter-list values: %w", err)
+		}
+		return name, values, nil
+
+	case len(args) > 0:
+		return "", nil, fmt.Errorf("unknown command %q for \"caliper\"", args[0])
 	}
 
-	// Display results to console
-	benchmark.PrintConsole(result)
+	return "", nil, nil
+}
 
-	// Save outputs
+// saveResult writes the JSON, CSV and Markdown outputs for a single benchmark result
+func saveResult(result *benchmark.Result, outputDir string, benchmarkName string) {
 	jsonPath := filepath.Join(outputDir, fmt.Sprintf("%s.json", benchmarkName))
 	if err := benchmark.SaveJSON(result, jsonPath); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to save JSON output: %v\n", err)
@@ -137,10 +708,53 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Markdown report saved to: %s\n", mdPath)
 	}
 
-	// Exit with appropriate code
-	if result.SuccessRate < 100.0 {
-		os.Exit(1)
+	if result.Config.LogOutput {
+		fmt.Printf("Run logs saved to: %s\n", result.Config.LogDir())
 	}
+	if result.Config.Checkpoint {
+		fmt.Printf("Checkpoint saved to: %s\n", result.Config.CheckpointPath())
+	}
+}
 
+// commandEnv combines the variables of an --env-file with --env values.
+// Later entries win, so --env overrides the file.
+func commandEnv(file string, vars []string) ([]string, error) {
+	var env []string
+	if file != "" {
+		fileEnv, err := benchmark.ParseEnvFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading --env-file: %w", err)
+		}
+		env = append(env, fileEnv...)
+	}
+
+	if err := benchmark.ValidateEnv(vars); err != nil {
+		return nil, fmt.Errorf("--env: %w", err)
+	}
+	return append(env, vars...), nil
+}
+
+// readinessProbe builds the readiness probe from the --ready-* flags
+func readinessProbe(tcp, http, output string, timeout time.Duration) benchmark.ReadinessProbe {
+	return benchmark.ReadinessProbe{TCP: tcp, HTTP: http, Output: output, Timeout: timeout}
+}
+
+// validateService checks the --service, --ready-* and --measure-startup flags
+func validateService(service string, probe benchmark.ReadinessProbe, measureStartup bool) error {
+	if probe.Timeout <= 0 {
+		return fmt.Errorf("--ready-timeout must be greater than 0")
+	}
+	if err := probe.Validate(); err != nil {
+		return err
+	}
+	if measureStartup && service != "" {
+		return fmt.Errorf("--service and --measure-startup cannot be used together: with --measure-startup the --command is the service")
+	}
+	if measureStartup && !probe.Configured() {
+		return fmt.Errorf("--measure-startup requires --ready-tcp, --ready-http or --ready-output")
+	}
+	if !measureStartup && service == "" && probe.Configured() {
+		return fmt.Errorf("--ready-tcp, --ready-http and --ready-output require --service or --measure-startup")
+	}
 	return nil
 }
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/attunehq/caliper/benchmark"
+	"github.com/attunehq/caliper/matrix"
+	"github.com/attunehq/caliper/schema"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <result.json>",
+	Short: "Check a saved result against the published JSON Schema",
+	Long: `Check that a JSON output of caliper loads and matches the published JSON
+Schema of its schemaVersion (schema/*.schema.json): a single benchmark result,
+a matrix summary (*_summary.json), a comparison of several commands
+(*_comparison.json), a parameter scan (*_scan.json), or the output of
+caliper compare --json.
+
+A result written by an older caliper is migrated to the current schema version
+first, as every command that reads results does, and the migrated document is
+validated. A result written by a newer caliper is rejected.`,
+	Example: `  caliper validate results/main.json
+  caliper validate results/build_summary.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var versioned struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(data, &versioned); err != nil {
+		return fmt.Errorf("%s: not a caliper result: %w", path, err)
+	}
+	kind, err := detectDocumentKind(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := benchmark.CheckSchemaVersion(versioned.SchemaVersion); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// A current document is validated as written, before loading it, so that
+	// type errors are reported with all other violations. An older one is
+	// validated as loaded, i.e. migrated to the current version.
+	if versioned.SchemaVersion != benchmark.SchemaVersion {
+		document, err := kind.load(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if data, err = json.Marshal(document); err != nil {
+			return err
+		}
+	}
+
+	violations, err := schema.Validate(kind.schema, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(violations) == 0 && versioned.SchemaVersion == benchmark.SchemaVersion {
+		if _, err := kind.load(data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	fmt.Printf("%s: %s, schema version %d\n", path, kind.name, versioned.SchemaVersion)
+	if versioned.SchemaVersion != benchmark.SchemaVersion {
+		fmt.Printf("Migrated to schema version %d on load\n", benchmark.SchemaVersion)
+	}
+	if len(violations) > 0 {
+		fmt.Printf("✗ Does not match %s:\n", kind.schema)
+		for _, violation := range violations {
+			fmt.Printf("  %s\n", violation)
+		}
+		return fmt.Errorf("%s: %d schema violation(s)", path, len(violations))
+	}
+	fmt.Printf("✓ Valid against %s\n", kind.schema)
+	return nil
+}
+
+// documentKind is a kind of JSON document written by caliper
+type documentKind struct {
+	name   string                         // Description for the output
+	schema string                         // File name of its JSON Schema
+	load   func(data []byte) (any, error) // Parses the document and migrates it to the current schema version
+}
+
+// detectDocumentKind tells the kinds of documents apart by their top-level fields
+func detectDocumentKind(data []byte) (documentKind, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return documentKind{}, fmt.Errorf("not a caliper result: %w", err)
+	}
+	has := func(field string) bool {
+		_, ok := top[field]
+		return ok
+	}
+
+	switch {
+	case has("runs"):
+		return documentKind{"benchmark result", schema.Result, func(data []byte) (any, error) {
+			return benchmark.ParseResultDocument(data)
+		}}, nil
+	case has("results"):
+		return documentKind{"matrix summary", schema.MatrixSummary, func(data []byte) (any, error) {
+			return matrix.ParseSummaryDocument(data)
+		}}, nil
+	case has("commands"):
+		return documentKind{"command comparison", schema.Comparison, func(data []byte) (any, error) {
+			var doc benchmark.ComparisonDocument
+			return parseOutputDocument(data, &doc, &doc.SchemaVersion)
+		}}, nil
+	case has("values"):
+		return documentKind{"parameter scan", schema.Scan, func(data []byte) (any, error) {
+			var doc benchmark.ScanDocument
+			return parseOutputDocument(data, &doc, &doc.SchemaVersion)
+		}}, nil
+	case has("configs"):
+		return documentKind{"matrix comparison", schema.MatrixDiff, func(data []byte) (any, error) {
+			var doc matrix.DiffDocument
+			return parseOutputDocument(data, &doc, &doc.SchemaVersion)
+		}}, nil
+	case has("verdict"):
+		return documentKind{"result comparison", schema.Diff, func(data []byte) (any, error) {
+			var doc benchmark.DiffDocument
+			return parseOutputDocument(data, &doc, &doc.SchemaVersion)
+		}}, nil
+	}
+	return documentKind{}, fmt.Errorf("not a caliper result, matrix summary, comparison or scan")
+}
+
+// parseOutputDocument decodes a document that caliper writes but never reads
+// back. Version 0 of these documents only lacked the schemaVersion field.
+func parseOutputDocument(data []byte, doc any, version *int) (any, error) {
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("not a caliper document: %w", err)
+	}
+	*version = benchmark.SchemaVersion
+	return doc, nil
+}
@@ -2,7 +2,10 @@ module github.com/attunehq/caliper
 
 go 1.24.2
 
-require github.com/docker/docker v27.0.0+incompatible
+require (
+	github.com/docker/docker v27.0.0+incompatible
+	github.com/spf13/cobra v1.10.2
+)
 
 require (
 	github.com/Microsoft/go-winio v0.4.21 // indirect
@@ -22,7 +25,6 @@ require (
 	github.com/opencontainers/go-digest v1.0.0 // indirect
 	github.com/opencontainers/image-spec v1.1.1 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.64.0 // indirect
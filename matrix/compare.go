@@ -0,0 +1,92 @@
+package matrix
+
+import (
+	"github.com/attunehq/caliper/benchmark"
+)
+
+// ConfigDiff compares one resource configuration of two matrix benchmarks
+type ConfigDiff struct {
+	Config    ResourceConfig
+	Baseline  *ConfigResult // nil when only the candidate tested the configuration
+	Candidate *ConfigResult // nil when only the baseline tested the configuration
+	Diff      benchmark.Diff
+}
+
+// Matched reports whether both benchmarks tested the configuration
+func (d ConfigDiff) Matched() bool {
+	return d.Baseline != nil && d.Candidate != nil
+}
+
+// MatrixDiff compares a candidate matrix benchmark against a baseline, configuration by configuration
+type MatrixDiff struct {
+	Baseline   string  // Name of the baseline summary
+	Candidate  string  // Name of the candidate summary
+	Confidence float64 // Confidence level of the intervals and tests
+	Configs    []ConfigDiff
+}
+
+// DiffMatrix compares the configurations of a candidate matrix benchmark
+// against those of a baseline, with the same tests as a comparison of two
+// single results (see diffConfigs). Configurations tested by only one side
+// are listed without a comparison.
+func DiffMatrix(baseline, candidate *MatrixResult, level float64) *MatrixDiff {
+	diff := &MatrixDiff{
+		Baseline:   baseline.Config.Name,
+		Candidate:  candidate.Config.Name,
+		Confidence: level,
+	}
+
+	candidates := make(map[ResourceConfig]*ConfigResult, len(candidate.Results))
+	for i := range candidate.Results {
+		candidates[candidate.Results[i].Config] = &candidate.Results[i]
+	}
+
+	for i := range baseline.Results {
+		base := &baseline.Results[i]
+		configDiff := ConfigDiff{Config: base.Config, Baseline: base}
+		if cand, ok := candidates[base.Config]; ok {
+			configDiff.Candidate = cand
+			configDiff.Diff = diffConfigs(base, cand, level)
+			delete(candidates, base.Config)
+		}
+		diff.Configs = append(diff.Configs, configDiff)
+	}
+
+	// Configurations only the candidate tested, in its order
+	for i := range candidate.Results {
+		if cand, ok := candidates[candidate.Results[i].Config]; ok {
+			diff.Configs = append(diff.Configs, ConfigDiff{Config: cand.Config, Candidate: cand})
+		}
+	}
+
+	return diff
+}
+
+// diffConfigs compares the results of one configuration. When both summaries
+// hold the per-run durations, they get Welch's t-test and the Mann–Whitney U
+// test; summaries written before the durations were saved only hold the
+// statistics, so Welch's t-test runs on those.
+func diffConfigs(base, cand *ConfigResult, level float64) benchmark.Diff {
+	if len(base.Durations) == 0 || len(cand.Durations) == 0 {
+		return benchmark.DiffSummaries(sampleSummary(base), sampleSummary(cand), level)
+	}
+	diff := benchmark.DiffDurations(base.Durations, cand.Durations, level)
+	diff.Baseline.Name = base.Config.String()
+	diff.Candidate.Name = cand.Config.String()
+	return diff
+}
+
+// sampleSummary returns the statistics of a configuration as a comparison sample
+func sampleSummary(r *ConfigResult) benchmark.SampleSummary {
+	n := r.SuccessRuns
+	if r.Trimmed {
+		n -= r.Outliers
+	}
+	return benchmark.SampleSummary{
+		Name:   r.Config.String(),
+		N:      n,
+		Mean:   r.Mean,
+		Median: r.Median,
+		StdDev: r.StdDev,
+	}
+}
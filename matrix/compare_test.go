@@ -0,0 +1,44 @@
+package matrix
+
+import (
+	"testing"
+
+	"github.com/attunehq/caliper/benchmark"
+)
+
+func TestDiffMatrix(t *testing.T) {
+	config := ResourceConfig{CPUs: 2, Memory: 4}
+	baselineDurations := []float64{10.1, 10.3, 9.9, 10.2, 10.0}
+	candidateDurations := []float64{9.1, 9.3, 8.9, 9.2, 9.0}
+	configResult := func(durations []float64) ConfigResult {
+		stats := benchmark.CalculateStatistics(durations)
+		return ConfigResult{
+			Config:      config,
+			Success:     true,
+			SuccessRuns: len(durations),
+			Mean:        stats.Mean,
+			Median:      stats.Median,
+			StdDev:      stats.StdDev,
+			Durations:   durations,
+		}
+	}
+	baseline := &MatrixResult{Results: []ConfigResult{configResult(baselineDurations)}}
+	candidate := &MatrixResult{Results: []ConfigResult{configResult(candidateDurations)}}
+
+	// With per-run durations, a configuration is compared like two single results
+	diff := DiffMatrix(baseline, candidate, 0.95).Configs[0].Diff
+	want := benchmark.DiffDurations(baselineDurations, candidateDurations, 0.95)
+	if !diff.MannWhitney || diff.MannWhitneyP != want.MannWhitneyP || diff.WelchP != want.WelchP || diff.Verdict != want.Verdict {
+		t.Errorf("diff = %+v, want the tests of DiffDurations %+v", diff, want)
+	}
+	if diff.Baseline.Name != "2 CPU, 4 GB" {
+		t.Errorf("baseline name = %q", diff.Baseline.Name)
+	}
+
+	// Older summaries without durations only get Welch's t-test on the statistics
+	baseline.Results[0].Durations = nil
+	diff = DiffMatrix(baseline, candidate, 0.95).Configs[0].Diff
+	if diff.MannWhitney || diff.Verdict != benchmark.VerdictFaster || diff.WelchP != want.WelchP {
+		t.Errorf("diff without durations = %+v, want Welch's t-test only, faster", diff)
+	}
+}
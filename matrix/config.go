@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/attunehq/caliper/benchmark"
 )
 
 // BenchmarkType represents the type of matrix benchmark being run
@@ -42,12 +45,53 @@ type Config struct {
 	Name       string           // Benchmark name for reports
 	Configs    []ResourceConfig // CPU/RAM configurations to test
 	SkipWarmup bool             // Skip warm-up run
+	Warmup     string           // Warm-up runs forwarded to the in-container runner: a count or "auto" ("" = default)
 	Debug      bool             // Enable debug logging with real-time output
+	Shell      string           // Shell for the benchmarked command and container helpers ("" = bash)
+	Calibrate  bool             // Subtract the measured shell spawn time from the statistics
+	Env        []string         // KEY=VALUE variables for the command and hooks, forwarded to the runner
+	CleanEnv   bool             // Start the command from a minimal allow-listed environment
+	Cwd        string           // Working directory of the command, relative to the repository checkout
 	Type       BenchmarkType    // Type of benchmark (custom, sweep-cpu, sweep-ram, all)
 	FixedCPU   int              // For sweep-ram: the fixed CPU value
 	FixedRAM   int              // For sweep-cpu: the fixed RAM value
 	CPUList    []int            // For all: list of CPU values tested
 	RAMList    []int            // For all: list of RAM values tested
+	Timeout    time.Duration    // Per-run timeout forwarded to the in-container runner (0 = none)
+	Setup      string           // Hook run once before all runs in each configuration
+	Prepare    string           // Hook run before every run (untimed)
+	Conclude   string           // Hook run after every run (untimed)
+	Cleanup    string           // Hook run once after all runs in each configuration
+
+	// Service under test, forwarded to the runner
+	Service        string                   // Command started before the runs and stopped after them
+	Readiness      benchmark.ReadinessProbe // When the service (or the command with MeasureStartup) is ready
+	MeasureStartup bool                     // Time the command from its start until Readiness passes
+
+	// Failure policy
+	ExpectedExitCodes []int   // Exit codes that count as success, forwarded to the runner (default: 0 only)
+	IgnoreFailure     bool    // Time every run regardless of exit code, forwarded to the runner
+	Retries           int     // Extra attempts for a failed run, forwarded to the runner
+	RetryOnExitCodes  []int   // Only retry runs exiting with these codes, forwarded to the runner
+	MinSuccessRate    float64 // Minimum percentage of successful runs for a configuration to succeed
+
+	// Outlier handling, forwarded to the runner
+	OutlierMethod string // iqr, mad or none ("" = default)
+	TrimOutliers  bool   // Exclude outlier runs from the statistics
+
+	Confidence  string    // Confidence level of the intervals, e.g. "99%" ("" = 95%), forwarded to the runner
+	Percentiles []float64 // Percentiles reported in addition to P90 and P95, forwarded to the runner
+
+	RequireQuietHost bool // Wait before each run until the host's background load is low, forwarded to the runner
+}
+
+// ConfidenceLevel returns the confidence level of the intervals, 95% unless set
+func (c Config) ConfidenceLevel() float64 {
+	level, err := benchmark.ParseConfidence(c.Confidence)
+	if err != nil {
+		return benchmark.DefaultConfidence
+	}
+	return level
 }
 
 // RepoName extracts the repository name from the RepoURL
@@ -66,19 +110,62 @@ func (c Config) RepoName() string {
 
 // ConfigResult holds the result for a single configuration
 type ConfigResult struct {
-	Config      ResourceConfig
-	Success     bool
-	Error       string
-	Mean        float64 // Mean duration in seconds
-	Median      float64 // Median duration in seconds
-	StdDev      float64 // Standard deviation in seconds
-	Min         float64 // Minimum duration in seconds
-	Max         float64 // Maximum duration in seconds
-	P90         float64 // 90th percentile in seconds
-	P95         float64 // 95th percentile in seconds
-	SuccessRate float64 // Percentage of successful runs
-	TotalRuns   int     // Total number of runs attempted
-	SuccessRuns int     // Number of successful runs
+	Config       ResourceConfig
+	Success      bool
+	Error        string
+	Mean         float64 // Mean duration in seconds
+	Median       float64 // Median duration in seconds
+	StdDev       float64 // Sample standard deviation in seconds
+	CV           float64 // Coefficient of variation: StdDev / Mean
+	MAD          float64 // Median absolute deviation in seconds
+	Min          float64 // Minimum duration in seconds
+	Max          float64 // Maximum duration in seconds
+	P90          float64 // 90th percentile in seconds
+	P95          float64 // 95th percentile in seconds
+	SuccessRate  float64 // Percentage of successful runs
+	TotalRuns    int     // Total number of runs attempted
+	SuccessRuns  int     // Number of successful runs
+	TimedOut     int     // Number of runs that exceeded the per-run timeout
+	HookFailures int     // Number of failed setup/prepare/conclude/cleanup hooks
+	Outliers     int     // Number of successful runs flagged as outliers
+	Trimmed      bool    // Statistics exclude the outliers
+
+	Percentiles []benchmark.Percentile // Requested percentiles (see Config.Percentiles)
+	Durations   []float64              // Durations the statistics were computed from, in seconds
+	Trend       benchmark.Trend        // Trend of the durations across the runs
+
+	// Host noise
+	HostSamples     []benchmark.HostSample // Background load sampled by the runner in the container
+	OtherContainers []string               // Other containers running on the Docker daemon, as "name (image)"
+
+	// Confidence intervals (zero with fewer than two successful runs)
+	Confidence      float64                      // Confidence level of the intervals, e.g. 0.95
+	MeanCI          benchmark.ConfidenceInterval // Mean, from Student's t-distribution
+	MeanBootstrapCI benchmark.ConfidenceInterval // Mean, percentile bootstrap
+	MedianCI        benchmark.ConfidenceInterval // Median, percentile bootstrap
+
+	// Diagnostics for the most recent failed run (empty when no run failed)
+	FailedRun      int    // Run number of the last failed run (0 for warm-up)
+	FailedExitCode int    // Exit code of the last failed run (-1 if killed or not started)
+	FailureExcerpt string // Last lines of stderr of the last failed run
+}
+
+// HasStats reports whether the configuration produced timing statistics,
+// which is the case whenever at least one run succeeded, even if the
+// configuration as a whole failed the success rate policy
+func (r ConfigResult) HasStats() bool {
+	return r.SuccessRuns > 0
+}
+
+// NoisyHostSamples returns the host samples that exceeded a noise threshold
+func (r ConfigResult) NoisyHostSamples() []benchmark.HostSample {
+	var noisy []benchmark.HostSample
+	for _, s := range r.HostSamples {
+		if s.Noisy() {
+			noisy = append(noisy, s)
+		}
+	}
+	return noisy
 }
 
 // MatrixResult holds the complete matrix benchmark results
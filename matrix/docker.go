@@ -43,6 +43,28 @@ func (d *DockerClient) Close() error {
 	return d.cli.Close()
 }
 
+// OtherContainers lists the running containers other than excludeID, as
+// "name (image)"
+func (d *DockerClient) OtherContainers(ctx context.Context, excludeID string) ([]string, error) {
+	containers, err := d.cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var others []string
+	for _, c := range containers {
+		if c.ID == excludeID {
+			continue
+		}
+		name := c.ID[:12]
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		others = append(others, fmt.Sprintf("%s (%s)", name, c.Image))
+	}
+	return others, nil
+}
+
 // ContainerConfig holds configuration for creating a container
 type ContainerConfig struct {
 	Image      string
@@ -50,12 +72,14 @@ type ContainerConfig struct {
 	Memory     int // GB
 	WorkingDir string
 	MountPath  string // Host path to mount at /workspace
+	Shell      string // Shell used by the ExecShell helpers (default: bash, "none" falls back to sh)
 }
 
 // Container represents a running Docker container
 type Container struct {
 	ID     string
 	client *DockerClient
+	shell  string
 }
 
 // EnsureImage checks if the image exists locally, pulls if not
@@ -148,6 +172,7 @@ func (d *DockerClient) CreateContainerWithDebug(ctx context.Context, cfg Contain
 	return &Container{
 		ID:     resp.ID,
 		client: d,
+		shell:  cfg.Shell,
 	}, nil
 }
 
@@ -198,9 +223,23 @@ func (c *Container) Exec(ctx context.Context, cmd []string, workDir string) (*Ex
 	}, nil
 }
 
+// shellArgs returns the argv that runs command with the container's shell.
+// The helpers always need a shell (e.g. for git clone and mkdir), so "none"
+// falls back to sh; only the benchmarked command itself is run without one.
+func (c *Container) shellArgs(command string) []string {
+	shell := c.shell
+	switch shell {
+	case "":
+		shell = "bash"
+	case "none":
+		shell = "sh"
+	}
+	return []string{shell, "-c", command}
+}
+
 // ExecShell executes a shell command in the container
 func (c *Container) ExecShell(ctx context.Context, command string, workDir string) (*ExecResult, error) {
-	return c.Exec(ctx, []string{"bash", "-c", command}, workDir)
+	return c.Exec(ctx, c.shellArgs(command), workDir)
 }
 
 // ExecShellStreaming executes a shell command in the container with real-time output streaming
@@ -209,7 +248,7 @@ func (c *Container) ExecShellStreaming(ctx context.Context, command string, work
 	debugLog(debug, "Working directory: %s", workDir)
 
 	execCfg := container.ExecOptions{
-		Cmd:          []string{"bash", "-c", command},
+		Cmd:          c.shellArgs(command),
 		WorkingDir:   workDir,
 		AttachStdout: true,
 		AttachStderr: true,
@@ -264,7 +303,7 @@ func (c *Container) ExecShellStreaming(ctx context.Context, command string, work
 func (c *Container) ExecShellWithDebug(ctx context.Context, command string, workDir string, debug bool) (*ExecResult, error) {
 	debugLog(debug, "Executing command: %s", command)
 	debugLog(debug, "Working directory: %s", workDir)
-	result, err := c.Exec(ctx, []string{"bash", "-c", command}, workDir)
+	result, err := c.Exec(ctx, c.shellArgs(command), workDir)
 	if err != nil {
 		debugLog(debug, "Command failed with error: %v", err)
 	} else {
@@ -0,0 +1,396 @@
+package matrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/attunehq/caliper/benchmark"
+)
+
+// SummaryDocument is the JSON document of a matrix benchmark, as saved by
+// SaveSummaryJSON and read by LoadMatrixResult. It shares
+// benchmark.SchemaVersion with the single result documents. Version 0 only
+// lacked fields, which load as zero values. Durations are in seconds.
+type SummaryDocument struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	Config        ConfigDocument         `json:"config"`
+	Results       []ConfigResultDocument `json:"results"`
+}
+
+// ConfigDocument is the configuration of a matrix benchmark
+type ConfigDocument struct {
+	Type              BenchmarkType             `json:"type"`
+	FixedCPU          int                       `json:"fixedCPU"` // sweep-ram only
+	FixedRAM          int                       `json:"fixedRAM"` // sweep-cpu only
+	CPUList           []int                     `json:"cpuList"`  // all only
+	RAMList           []int                     `json:"ramList"`  // all only
+	Image             string                    `json:"image"`
+	RepoURL           string                    `json:"repoURL"`
+	Command           string                    `json:"command"`
+	Runs              int                       `json:"runs"`
+	OutputDir         string                    `json:"outputDir"`
+	Name              string                    `json:"name"`
+	SkipWarmup        bool                      `json:"skipWarmup"`
+	Warmup            string                    `json:"warmup"`
+	Timeout           float64                   `json:"timeout"`
+	Shell             string                    `json:"shell"`
+	Calibrate         bool                      `json:"calibrate"`
+	Env               map[string]string         `json:"env"` // Secret values redacted
+	CleanEnv          bool                      `json:"cleanEnv"`
+	Cwd               string                    `json:"cwd"`
+	ExpectedExitCodes []int                     `json:"expectedExitCodes"`
+	IgnoreFailure     bool                      `json:"ignoreFailure"`
+	Retries           int                       `json:"retries"`
+	RetryOnExitCodes  []int                     `json:"retryOnExitCodes"`
+	MinSuccessRate    float64                   `json:"minSuccessRate"`
+	OutlierMethod     string                    `json:"outlierMethod"`
+	TrimOutliers      bool                      `json:"trimOutliers"`
+	Confidence        float64                   `json:"confidence"`
+	Percentiles       []float64                 `json:"percentiles"`
+	RequireQuietHost  bool                      `json:"requireQuietHost"`
+	Hooks             benchmark.HooksDocument   `json:"hooks"`
+	Service           benchmark.ServiceDocument `json:"service"`
+}
+
+// ConfigResultDocument is the result of one resource configuration
+type ConfigResultDocument struct {
+	Config         ResourceConfigDocument    `json:"config"`
+	Success        bool                      `json:"success"`
+	Error          string                    `json:"error"`
+	TotalRuns      int                       `json:"totalRuns"`
+	SuccessRuns    int                       `json:"successRuns"`
+	SuccessRate    float64                   `json:"successRate"`
+	TimedOut       int                       `json:"timedOut"`
+	HookFailures   int                       `json:"hookFailures"`
+	Outliers       int                       `json:"outliers"`
+	Trimmed        bool                      `json:"trimmed"`
+	Host           HostDocument              `json:"host"`
+	FailedRun      int                       `json:"failedRun"`      // Last failed run (0 for warm-up)
+	FailedExitCode int                       `json:"failedExitCode"` // Exit code of the last failed run
+	FailureExcerpt string                    `json:"failureExcerpt"` // Last lines of its stderr (empty when no run failed)
+	Statistics     *ConfigStatisticsDocument `json:"statistics,omitempty"`
+}
+
+// ResourceConfigDocument is a CPU/RAM configuration
+type ResourceConfigDocument struct {
+	CPUs   int `json:"cpus"`
+	Memory int `json:"memory"` // GB
+}
+
+// HostDocument holds the host noise of a configuration
+type HostDocument struct {
+	Samples         []benchmark.HostSample `json:"samples"`
+	NoisySamples    int                    `json:"noisySamples"`
+	OtherContainers []string               `json:"otherContainers"`
+}
+
+// ConfigStatisticsDocument holds the statistics of a configuration with successful runs
+type ConfigStatisticsDocument struct {
+	Mean                float64                               `json:"mean"`
+	Median              float64                               `json:"median"`
+	StdDev              float64                               `json:"stdDev"`
+	CV                  float64                               `json:"cv"`
+	MAD                 float64                               `json:"mad"`
+	Min                 float64                               `json:"min"`
+	Max                 float64                               `json:"max"`
+	P90                 float64                               `json:"p90"`
+	P95                 float64                               `json:"p95"`
+	Percentiles         []benchmark.Percentile                `json:"percentiles"`
+	Estimators          benchmark.EstimatorsDocument          `json:"estimators"`
+	ConfidenceIntervals benchmark.ConfidenceIntervalsDocument `json:"confidenceIntervals"`
+	Trend               benchmark.TrendDocument               `json:"trend"`
+	Durations           []float64                             `json:"durations"` // Durations the statistics were computed from, in run order
+}
+
+// NewSummaryDocument converts a matrix result to its JSON document
+func NewSummaryDocument(result *MatrixResult) SummaryDocument {
+	config := result.Config
+	doc := SummaryDocument{
+		SchemaVersion: benchmark.SchemaVersion,
+		Config: ConfigDocument{
+			Type:              config.Type,
+			FixedCPU:          config.FixedCPU,
+			FixedRAM:          config.FixedRAM,
+			CPUList:           config.CPUList,
+			RAMList:           config.RAMList,
+			Image:             config.Image,
+			RepoURL:           config.RepoURL,
+			Command:           config.Command,
+			Runs:              config.Runs,
+			OutputDir:         config.OutputDir,
+			Name:              config.Name,
+			SkipWarmup:        config.SkipWarmup,
+			Warmup:            config.Warmup,
+			Timeout:           config.Timeout.Seconds(),
+			Shell:             config.Shell,
+			Calibrate:         config.Calibrate,
+			Env:               benchmark.RedactEnv(config.Env),
+			CleanEnv:          config.CleanEnv,
+			Cwd:               config.Cwd,
+			ExpectedExitCodes: config.ExpectedExitCodes,
+			IgnoreFailure:     config.IgnoreFailure,
+			Retries:           config.Retries,
+			RetryOnExitCodes:  config.RetryOnExitCodes,
+			MinSuccessRate:    config.MinSuccessRate,
+			OutlierMethod:     config.OutlierMethod,
+			TrimOutliers:      config.TrimOutliers,
+			Confidence:        config.ConfidenceLevel(),
+			Percentiles:       config.Percentiles,
+			RequireQuietHost:  config.RequireQuietHost,
+			Hooks: benchmark.HooksDocument{
+				Setup:    config.Setup,
+				Prepare:  config.Prepare,
+				Conclude: config.Conclude,
+				Cleanup:  config.Cleanup,
+			},
+			Service: benchmark.ServiceDocument{
+				Command:        config.Service,
+				MeasureStartup: config.MeasureStartup,
+				Readiness: benchmark.ReadinessDocument{
+					TCP:     config.Readiness.TCP,
+					HTTP:    config.Readiness.HTTP,
+					Output:  config.Readiness.Output,
+					Timeout: config.Readiness.Timeout.Seconds(),
+				},
+			},
+		},
+		Results: make([]ConfigResultDocument, 0, len(result.Results)),
+	}
+
+	for _, r := range result.Results {
+		resultDoc := ConfigResultDocument{
+			Config:       ResourceConfigDocument{CPUs: r.Config.CPUs, Memory: r.Config.Memory},
+			Success:      r.Success,
+			Error:        r.Error,
+			TotalRuns:    r.TotalRuns,
+			SuccessRuns:  r.SuccessRuns,
+			SuccessRate:  r.SuccessRate,
+			TimedOut:     r.TimedOut,
+			HookFailures: r.HookFailures,
+			Outliers:     r.Outliers,
+			Trimmed:      r.Trimmed,
+			Host: HostDocument{
+				Samples:         hostSamplesJSON(r.HostSamples),
+				NoisySamples:    len(r.NoisyHostSamples()),
+				OtherContainers: otherContainersJSON(r.OtherContainers),
+			},
+			FailedRun:      r.FailedRun,
+			FailedExitCode: r.FailedExitCode,
+			FailureExcerpt: r.FailureExcerpt,
+		}
+
+		if r.HasStats() {
+			resultDoc.Statistics = &ConfigStatisticsDocument{
+				Mean:        r.Mean,
+				Median:      r.Median,
+				StdDev:      r.StdDev,
+				CV:          r.CV,
+				MAD:         r.MAD,
+				Min:         r.Min,
+				Max:         r.Max,
+				P90:         r.P90,
+				P95:         r.P95,
+				Percentiles: r.Percentiles,
+				Estimators: benchmark.EstimatorsDocument{
+					StdDev:     benchmark.StdDevEstimator,
+					MAD:        benchmark.MADEstimator,
+					Percentile: benchmark.PercentileEstimator,
+				},
+				ConfidenceIntervals: benchmark.ConfidenceIntervalsDocument{
+					Level:           r.Confidence,
+					MeanT:           r.MeanCI,
+					MeanBootstrap:   r.MeanBootstrapCI,
+					MedianBootstrap: r.MedianCI,
+				},
+				Trend:     benchmark.TrendDocument{Trend: r.Trend, Drifting: r.Trend.Drifting()},
+				Durations: r.Durations,
+			}
+		}
+
+		doc.Results = append(doc.Results, resultDoc)
+	}
+
+	return doc
+}
+
+// MatrixResult converts the document back to a matrix result
+func (d SummaryDocument) MatrixResult() *MatrixResult {
+	c := d.Config
+	result := &MatrixResult{
+		Config: Config{
+			Type:              c.Type,
+			FixedCPU:          c.FixedCPU,
+			FixedRAM:          c.FixedRAM,
+			CPUList:           c.CPUList,
+			RAMList:           c.RAMList,
+			Image:             c.Image,
+			RepoURL:           c.RepoURL,
+			Command:           c.Command,
+			Runs:              c.Runs,
+			OutputDir:         c.OutputDir,
+			Name:              c.Name,
+			SkipWarmup:        c.SkipWarmup,
+			Warmup:            c.Warmup,
+			Shell:             c.Shell,
+			Calibrate:         c.Calibrate,
+			Env:               envEntries(c.Env),
+			CleanEnv:          c.CleanEnv,
+			Cwd:               c.Cwd,
+			Timeout:           time.Duration(c.Timeout * float64(time.Second)),
+			Setup:             c.Hooks.Setup,
+			Prepare:           c.Hooks.Prepare,
+			Conclude:          c.Hooks.Conclude,
+			Cleanup:           c.Hooks.Cleanup,
+			Service:           c.Service.Command,
+			MeasureStartup:    c.Service.MeasureStartup,
+			ExpectedExitCodes: c.ExpectedExitCodes,
+			IgnoreFailure:     c.IgnoreFailure,
+			Retries:           c.Retries,
+			RetryOnExitCodes:  c.RetryOnExitCodes,
+			MinSuccessRate:    c.MinSuccessRate,
+			OutlierMethod:     c.OutlierMethod,
+			TrimOutliers:      c.TrimOutliers,
+			Percentiles:       c.Percentiles,
+			RequireQuietHost:  c.RequireQuietHost,
+			Readiness: benchmark.ReadinessProbe{
+				TCP:     c.Service.Readiness.TCP,
+				HTTP:    c.Service.Readiness.HTTP,
+				Output:  c.Service.Readiness.Output,
+				Timeout: time.Duration(c.Service.Readiness.Timeout * float64(time.Second)),
+			},
+		},
+	}
+	if c.Confidence > 0 {
+		result.Config.Confidence = benchmark.FormatConfidence(c.Confidence)
+	}
+
+	for _, r := range d.Results {
+		configResult := ConfigResult{
+			Config:          ResourceConfig{CPUs: r.Config.CPUs, Memory: r.Config.Memory},
+			Success:         r.Success,
+			Error:           r.Error,
+			SuccessRate:     r.SuccessRate,
+			TotalRuns:       r.TotalRuns,
+			SuccessRuns:     r.SuccessRuns,
+			TimedOut:        r.TimedOut,
+			HookFailures:    r.HookFailures,
+			Outliers:        r.Outliers,
+			Trimmed:         r.Trimmed,
+			HostSamples:     r.Host.Samples,
+			OtherContainers: r.Host.OtherContainers,
+			FailedRun:       r.FailedRun,
+			FailedExitCode:  r.FailedExitCode,
+			FailureExcerpt:  r.FailureExcerpt,
+		}
+		if s := r.Statistics; s != nil {
+			configResult.Mean = s.Mean
+			configResult.Median = s.Median
+			configResult.StdDev = s.StdDev
+			configResult.CV = s.CV
+			configResult.MAD = s.MAD
+			configResult.Min = s.Min
+			configResult.Max = s.Max
+			configResult.P90 = s.P90
+			configResult.P95 = s.P95
+			configResult.Percentiles = s.Percentiles
+			configResult.Confidence = s.ConfidenceIntervals.Level
+			configResult.MeanCI = s.ConfidenceIntervals.MeanT
+			configResult.MeanBootstrapCI = s.ConfidenceIntervals.MeanBootstrap
+			configResult.MedianCI = s.ConfidenceIntervals.MedianBootstrap
+			configResult.Trend = s.Trend.Trend
+			configResult.Durations = s.Durations
+		}
+		result.Results = append(result.Results, configResult)
+	}
+
+	return result
+}
+
+// DiffDocument is the JSON document of the comparison of two matrix
+// benchmarks, as saved by SaveDiffJSON
+type DiffDocument struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	Baseline      string               `json:"baseline"`
+	Candidate     string               `json:"candidate"`
+	Confidence    float64              `json:"confidence"`
+	Configs       []ConfigDiffDocument `json:"configs"`
+	Summary       string               `json:"summary"`
+}
+
+// ConfigDiffDocument is the comparison of one resource configuration
+type ConfigDiffDocument struct {
+	Config     ResourceConfigDocument        `json:"config"`
+	Matched    bool                          `json:"matched"`
+	Comparison *benchmark.DiffResultDocument `json:"comparison,omitempty"` // Only when both benchmarks tested the configuration
+}
+
+// NewDiffDocument converts a comparison of two matrix benchmarks to its JSON document
+func NewDiffDocument(diff *MatrixDiff) DiffDocument {
+	doc := DiffDocument{
+		SchemaVersion: benchmark.SchemaVersion,
+		Baseline:      diff.Baseline,
+		Candidate:     diff.Candidate,
+		Confidence:    diff.Confidence,
+		Configs:       make([]ConfigDiffDocument, 0, len(diff.Configs)),
+		Summary:       diffSummary(diff),
+	}
+	for _, d := range diff.Configs {
+		config := ConfigDiffDocument{
+			Config:  ResourceConfigDocument{CPUs: d.Config.CPUs, Memory: d.Config.Memory},
+			Matched: d.Matched(),
+		}
+		if d.Matched() {
+			comparison := benchmark.NewDiffResultDocument(d.Diff)
+			config.Comparison = &comparison
+		}
+		doc.Configs = append(doc.Configs, config)
+	}
+	return doc
+}
+
+// LoadSummaryDocument reads a matrix summary JSON file saved by SaveSummaryJSON
+func LoadSummaryDocument(path string) (*SummaryDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSummaryDocument(data)
+}
+
+// ParseSummaryDocument parses a matrix summary JSON document
+func ParseSummaryDocument(data []byte) (*SummaryDocument, error) {
+	var doc SummaryDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("not a matrix summary: %w", err)
+	}
+	if doc.Results == nil {
+		return nil, fmt.Errorf("not a matrix summary: no results")
+	}
+	if err := benchmark.CheckSchemaVersion(doc.SchemaVersion); err != nil {
+		return nil, err
+	}
+	doc.SchemaVersion = benchmark.SchemaVersion
+	return &doc, nil
+}
+
+// LoadMatrixResult reads a matrix summary JSON file saved by SaveSummaryJSON, of any schema version
+func LoadMatrixResult(path string) (*MatrixResult, error) {
+	doc, err := LoadSummaryDocument(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc.MatrixResult(), nil
+}
+
+// envEntries converts an environment map to sorted KEY=VALUE entries
+func envEntries(env map[string]string) []string {
+	entries := make([]string, 0, len(env))
+	for key, value := range env {
+		entries = append(entries, key+"="+value)
+	}
+	sort.Strings(entries)
+	return entries
+}
@@ -0,0 +1,246 @@
+package matrix
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/attunehq/caliper/benchmark"
+)
+
+// SaveSummaryHTML saves the matrix results as an HTML report. It holds the
+// same information as the Markdown report, plus a chart of the mean durations.
+func SaveSummaryHTML(result *MatrixResult, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	config := result.Config
+	report := benchmark.HTMLReport{
+		Title:     "Matrix Benchmark Report",
+		Generated: time.Now().Format(time.RFC1123),
+	}
+	if config.Name != "" {
+		report.Title += ": " + config.Name
+	}
+
+	// Configuration
+	fields := []benchmark.HTMLField{}
+	if config.Type != "" {
+		fields = append(fields, benchmark.HTMLField{Label: "Benchmark Type", Value: string(config.Type)})
+	}
+	fields = append(fields,
+		benchmark.HTMLField{Label: "Docker Image", Value: config.Image, Code: true},
+		benchmark.HTMLField{Label: "Repository", Value: config.RepoURL},
+		benchmark.HTMLField{Label: "Command", Value: config.Command, Code: true},
+		benchmark.HTMLField{Label: "Runs per Config", Value: fmt.Sprintf("%d", config.Runs)},
+	)
+	if config.Timeout > 0 {
+		fields = append(fields, benchmark.HTMLField{Label: "Run Timeout", Value: config.Timeout.String()})
+	}
+	for _, hook := range []struct{ label, command string }{
+		{"Setup", config.Setup},
+		{"Prepare (each run, untimed)", config.Prepare},
+		{"Conclude (each run, untimed)", config.Conclude},
+		{"Cleanup", config.Cleanup},
+	} {
+		if hook.command != "" {
+			fields = append(fields, benchmark.HTMLField{Label: hook.label, Value: hook.command, Code: true})
+		}
+	}
+	if config.Service != "" {
+		fields = append(fields, benchmark.HTMLField{Label: "Service", Value: fmt.Sprintf("%s (ready: %s)", config.Service, config.Readiness.Description())})
+	}
+	if config.MeasureStartup {
+		fields = append(fields, benchmark.HTMLField{Label: "Measured", Value: fmt.Sprintf("startup until ready (%s)", config.Readiness.Description())})
+	}
+	if config.Shell != "" {
+		fields = append(fields, benchmark.HTMLField{Label: "Shell", Value: config.Shell})
+	}
+	if config.Cwd != "" {
+		fields = append(fields, benchmark.HTMLField{Label: "Working Directory", Value: config.Cwd, Code: true})
+	}
+	if env := formatEnv(config); env != "" {
+		fields = append(fields, benchmark.HTMLField{Label: "Environment", Value: env, Code: true})
+	}
+	if len(config.ExpectedExitCodes) > 0 {
+		fields = append(fields, benchmark.HTMLField{Label: "Expected Exit Codes", Value: formatIntList(config.ExpectedExitCodes)})
+	}
+	if config.IgnoreFailure {
+		fields = append(fields, benchmark.HTMLField{Label: "Ignore Failures", Value: "every completed run is timed, whatever its exit code"})
+	}
+	if config.Retries > 0 {
+		fields = append(fields, benchmark.HTMLField{Label: "Retries", Value: fmt.Sprintf("up to %d per failed run", config.Retries)})
+	}
+	fields = append(fields, benchmark.HTMLField{Label: "Minimum Success Rate", Value: fmt.Sprintf("%g%%", config.MinSuccessRate)})
+	if config.TrimOutliers {
+		fields = append(fields, benchmark.HTMLField{Label: "Outliers", Value: "excluded from the statistics"})
+	}
+	if config.RequireQuietHost {
+		fields = append(fields, benchmark.HTMLField{Label: "Quiet Host", Value: "each run waited for low background load"})
+	}
+	fields = append(fields, benchmark.HTMLField{Label: "Confidence Level", Value: benchmark.FormatConfidence(config.ConfidenceLevel())})
+
+	// Type-specific configuration
+	switch config.Type {
+	case BenchmarkTypeSweepCPU:
+		fields = append(fields,
+			benchmark.HTMLField{Label: "Fixed RAM", Value: fmt.Sprintf("%d GB", config.FixedRAM)},
+			benchmark.HTMLField{Label: "CPU Values Tested", Value: formatIntList(config.CPUList)},
+		)
+	case BenchmarkTypeSweepRAM:
+		fields = append(fields,
+			benchmark.HTMLField{Label: "Fixed CPU", Value: fmt.Sprintf("%d", config.FixedCPU)},
+			benchmark.HTMLField{Label: "RAM Values Tested", Value: formatIntList(config.RAMList) + " GB"},
+		)
+	case BenchmarkTypeAll:
+		fields = append(fields,
+			benchmark.HTMLField{Label: "CPU Values Tested", Value: formatIntList(config.CPUList)},
+			benchmark.HTMLField{Label: "RAM Values Tested", Value: formatIntList(config.RAMList) + " GB"},
+		)
+	}
+
+	switch {
+	case config.SkipWarmup:
+		fields = append(fields, benchmark.HTMLField{Label: "Warm-up", Value: "Disabled"})
+	case config.Warmup != "":
+		fields = append(fields, benchmark.HTMLField{Label: "Warm-up", Value: config.Warmup + " (excluded from stats)"})
+	default:
+		fields = append(fields, benchmark.HTMLField{Label: "Warm-up", Value: "Enabled (excluded from stats)"})
+	}
+	report.Sections = append(report.Sections, benchmark.HTMLSection{Title: "Configuration", Fields: fields})
+
+	// Summary table and chart of the means
+	ciHeader := benchmark.FormatConfidence(config.ConfidenceLevel()) + " CI"
+	table := &benchmark.HTMLTable{Header: []string{"CPUs", "RAM", "Mean", ciHeader, "Median", "Std Dev", "Min", "Max", "Distribution", "Outliers", "Success Rate"}}
+	var labels []string
+	var means []float64
+	var failed []bool
+	for _, r := range result.Results {
+		if r.HasStats() {
+			labels = append(labels, r.Config.String())
+			means = append(means, r.Mean)
+			failed = append(failed, !r.Success)
+			table.Rows = append(table.Rows, []string{
+				fmt.Sprintf("%d", r.Config.CPUs),
+				fmt.Sprintf("%d GB", r.Config.Memory),
+				formatDuration(r.Mean),
+				formatInterval(r.MeanCI),
+				formatDuration(r.Median),
+				formatDuration(r.StdDev),
+				formatDuration(r.Min),
+				formatDuration(r.Max),
+				formatSparkline(r),
+				formatOutliers(r),
+				formatSuccessRate(r),
+			})
+		} else {
+			table.Rows = append(table.Rows, []string{
+				fmt.Sprintf("%d", r.Config.CPUs),
+				fmt.Sprintf("%d GB", r.Config.Memory),
+				"FAILED", "-", "-", "-", "-", "-", "-", "-", "0%",
+			})
+		}
+	}
+	report.Sections = append(report.Sections, benchmark.HTMLSection{
+		Title: "Results Summary",
+		Text:  "Mean duration of each configuration with successful runs; failed configurations in red.",
+		Bars:  benchmark.NewHTMLBars(labels, means, failed),
+		Table: table,
+	})
+
+	// Detailed statistics
+	for _, r := range result.Results {
+		report.Sections = append(report.Sections, configHTMLSection(r))
+	}
+
+	// Failed configurations section if any
+	if failedResults := failedConfigs(result); len(failedResults) > 0 {
+		table := &benchmark.HTMLTable{Header: []string{"Configuration", "Failure", "Stderr"}}
+		for _, r := range failedResults {
+			table.Rows = append(table.Rows, []string{r.Config.String(), failureSummary(r), r.FailureExcerpt})
+		}
+		report.Sections = append(report.Sections, benchmark.HTMLSection{Title: "Failed Configurations", Table: table})
+	}
+
+	if graphs := generateGraphStrings(result); len(graphs) > 0 {
+		report.Sections = append(report.Sections, benchmark.HTMLSection{Title: "Graphs", Pre: strings.Join(graphs, "\n")})
+	}
+
+	return report.Write(file)
+}
+
+// configHTMLSection describes one configuration in the HTML report
+func configHTMLSection(r ConfigResult) benchmark.HTMLSection {
+	section := benchmark.HTMLSection{Title: r.Config.String()}
+	if !r.HasStats() {
+		section.Text = "Failed: " + r.Error
+		return section
+	}
+	if !r.Success {
+		section.Text = fmt.Sprintf("Status: Failed (%s)", r.Error)
+	}
+
+	level := benchmark.FormatConfidence(r.Confidence)
+	table := &benchmark.HTMLTable{Header: []string{"Metric", "Value"}}
+	add := func(metric, value string) {
+		table.Rows = append(table.Rows, []string{metric, value})
+	}
+	add("Mean", fmt.Sprintf("%s (%.3fs)", formatDuration(r.Mean), r.Mean))
+	if r.MeanCI.Valid() {
+		add(fmt.Sprintf("Mean %s CI (t)", level), formatInterval(r.MeanCI))
+		add(fmt.Sprintf("Mean %s CI (bootstrap)", level), formatInterval(r.MeanBootstrapCI))
+	}
+	add("Median", fmt.Sprintf("%s (%.3fs)", formatDuration(r.Median), r.Median))
+	if r.MedianCI.Valid() {
+		add(fmt.Sprintf("Median %s CI (bootstrap)", level), formatInterval(r.MedianCI))
+	}
+	add("Std Dev", fmt.Sprintf("%s (%.3fs)", formatDuration(r.StdDev), r.StdDev))
+	add("CV", fmt.Sprintf("%.2f%%", r.CV*100))
+	add("MAD", fmt.Sprintf("%s (%.3fs)", formatDuration(r.MAD), r.MAD))
+	add("Min", fmt.Sprintf("%s (%.3fs)", formatDuration(r.Min), r.Min))
+	add("Max", fmt.Sprintf("%s (%.3fs)", formatDuration(r.Max), r.Max))
+	add("P90", fmt.Sprintf("%s (%.3fs)", formatDuration(r.P90), r.P90))
+	add("P95", fmt.Sprintf("%s (%.3fs)", formatDuration(r.P95), r.P95))
+	for _, p := range r.Percentiles {
+		add(p.Label(), fmt.Sprintf("%s (%.3fs)", formatDuration(p.Value), p.Value))
+	}
+	add("Success Rate", fmt.Sprintf("%.1f%% (%d/%d)", r.SuccessRate, r.SuccessRuns, r.TotalRuns))
+	if r.TimedOut > 0 {
+		add("Timed Out Runs", fmt.Sprintf("%d", r.TimedOut))
+	}
+	if r.HookFailures > 0 {
+		add("Hook Failures", fmt.Sprintf("%d", r.HookFailures))
+	}
+	if r.Outliers > 0 {
+		add("Outliers", formatOutliers(r))
+	}
+	if r.Trend.Valid() {
+		add("Trend", r.Trend.String())
+	}
+	if len(r.HostSamples) > 0 {
+		add("Host Noise", fmt.Sprintf("%d of %d samples noisy", len(r.NoisyHostSamples()), len(r.HostSamples)))
+	}
+	if len(r.OtherContainers) > 0 {
+		add("Other Containers", strings.Join(r.OtherContainers, ", "))
+	}
+	section.Table = table
+
+	if isNoisy(r) {
+		section.Warnings = append(section.Warnings, fmt.Sprintf("The host was busy: %s. The runs may be slower than usual.", hostNoiseSummary(r)))
+	}
+	if r.Trend.Drifting() {
+		section.Warnings = append(section.Warnings, benchmark.DriftWarning(r.Trend))
+	}
+	if len(r.Durations) > 1 {
+		histogram := benchmark.NewHistogram(r.Durations)
+		section.Pre = histogram.String()
+		if histogram.MultiModal() {
+			section.Warnings = append(section.Warnings, benchmark.MultiModalWarning(histogram))
+		}
+	}
+	return section
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
+
+	"github.com/attunehq/caliper/benchmark"
 )
 
 // PrintSummaryTable prints a formatted summary table to the console
@@ -26,24 +29,27 @@ func PrintSummaryTable(result *MatrixResult) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
 	// Print header
-	fmt.Fprintf(w, "CPUs\tRAM\tMean\tMedian\tStd Dev\tMin\tMax\tSuccess\n")
-	fmt.Fprintf(w, "----\t---\t----\t------\t-------\t---\t---\t-------\n")
+	ciHeader := benchmark.FormatConfidence(result.Config.ConfidenceLevel()) + " CI"
+	fmt.Fprintf(w, "CPUs\tRAM\tMean\t%s\tMedian\tStd Dev\tMin\tMax\tOutliers\tSuccess\n", ciHeader)
+	fmt.Fprintf(w, "----\t---\t----\t%s\t------\t-------\t---\t---\t--------\t-------\n", strings.Repeat("-", len(ciHeader)))
 
 	// Print each result
 	for _, r := range result.Results {
-		if r.Success {
-			fmt.Fprintf(w, "%d\t%d GB\t%s\t%s\t%s\t%s\t%s\t%.0f%%\n",
+		if r.HasStats() {
+			fmt.Fprintf(w, "%d\t%d GB\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 				r.Config.CPUs,
 				r.Config.Memory,
 				formatDuration(r.Mean),
+				formatInterval(r.MeanCI),
 				formatDuration(r.Median),
 				formatDuration(r.StdDev),
 				formatDuration(r.Min),
 				formatDuration(r.Max),
-				r.SuccessRate,
+				formatOutliers(r),
+				formatSuccessRate(r),
 			)
 		} else {
-			fmt.Fprintf(w, "%d\t%d GB\tFAILED\t-\t-\t-\t-\t0%%\n",
+			fmt.Fprintf(w, "%d\t%d GB\tFAILED\t-\t-\t-\t-\t-\t-\t0%%\n",
 				r.Config.CPUs,
 				r.Config.Memory,
 			)
@@ -52,67 +58,41 @@ func PrintSummaryTable(result *MatrixResult) {
 
 	w.Flush()
 
-	// Print failed configurations if any
-	var failed []ConfigResult
-	for _, r := range result.Results {
-		if !r.Success {
-			failed = append(failed, r)
+	// Print configurations whose runs drifted
+	if drifting := driftingConfigs(result); len(drifting) > 0 {
+		fmt.Printf("\nDrifting Configurations:\n")
+		for _, r := range drifting {
+			fmt.Printf("  ⚠ %s: runs got %s, %s\n", r.Config.String(), r.Trend.Direction(), r.Trend)
 		}
 	}
 
+	// Print configurations measured on a busy host
+	if noisy := noisyConfigs(result); len(noisy) > 0 {
+		fmt.Printf("\nNoisy Configurations:\n")
+		for _, r := range noisy {
+			fmt.Printf("  ⚠ %s: %s\n", r.Config.String(), hostNoiseSummary(r))
+		}
+	}
+
+	// Print failed configurations if any
+	failed := failedConfigs(result)
 	if len(failed) > 0 {
 		fmt.Printf("\nFailed Configurations:\n")
 		for _, r := range failed {
-			fmt.Printf("  - %s: %s\n", r.Config.String(), r.Error)
+			fmt.Printf("  - %s: %s\n", r.Config.String(), failureSummary(r))
+			if r.FailureExcerpt != "" {
+				for _, line := range strings.Split(r.FailureExcerpt, "\n") {
+					fmt.Printf("      │ %s\n", line)
+				}
+			}
 		}
 	}
 
 	fmt.Printf("\n")
 }
 
-// SaveSummaryJSON saves the matrix results as JSON
+// SaveSummaryJSON saves the matrix results as a SummaryDocument
 func SaveSummaryJSON(result *MatrixResult, filename string) error {
-	output := map[string]interface{}{
-		"config": map[string]interface{}{
-			"image":      result.Config.Image,
-			"repoURL":    result.Config.RepoURL,
-			"command":    result.Config.Command,
-			"runs":       result.Config.Runs,
-			"outputDir":  result.Config.OutputDir,
-			"name":       result.Config.Name,
-			"skipWarmup": result.Config.SkipWarmup,
-		},
-		"results": make([]map[string]interface{}, 0, len(result.Results)),
-	}
-
-	for _, r := range result.Results {
-		resultMap := map[string]interface{}{
-			"config": map[string]interface{}{
-				"cpus":   r.Config.CPUs,
-				"memory": r.Config.Memory,
-			},
-			"success":     r.Success,
-			"error":       r.Error,
-			"totalRuns":   r.TotalRuns,
-			"successRuns": r.SuccessRuns,
-			"successRate": r.SuccessRate,
-		}
-
-		if r.Success {
-			resultMap["statistics"] = map[string]interface{}{
-				"mean":   r.Mean,
-				"median": r.Median,
-				"stdDev": r.StdDev,
-				"min":    r.Min,
-				"max":    r.Max,
-				"p90":    r.P90,
-				"p95":    r.P95,
-			}
-		}
-
-		output["results"] = append(output["results"].([]map[string]interface{}), resultMap)
-	}
-
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -121,7 +101,7 @@ func SaveSummaryJSON(result *MatrixResult, filename string) error {
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+	return encoder.Encode(NewSummaryDocument(result))
 }
 
 // SaveSummaryCSV saves the matrix results as CSV
@@ -140,8 +120,17 @@ func SaveSummaryCSV(result *MatrixResult, filename string) error {
 		"CPUs", "Memory (GB)", "Success",
 		"Mean (s)", "Median (s)", "Std Dev (s)",
 		"Min (s)", "Max (s)", "P90 (s)", "P95 (s)",
-		"Success Rate (%)", "Total Runs", "Successful Runs", "Error",
+		"CV", "MAD (s)",
 	}
+	for _, p := range result.Config.Percentiles {
+		header = append(header, benchmark.Percentile{Percentile: p}.Label()+" (s)")
+	}
+	header = append(header,
+		"Mean CI Low (s)", "Mean CI High (s)", "Median CI Low (s)", "Median CI High (s)",
+		"Trend Slope (s/run)", "Trend Drift", "Trend P-Value",
+		"Success Rate (%)", "Total Runs", "Successful Runs", "Timed Out Runs", "Outliers",
+		"Noisy Host Samples", "Other Containers", "Error",
+	)
 	if err := writer.Write(header); err != nil {
 		return err
 	}
@@ -159,11 +148,29 @@ func SaveSummaryCSV(result *MatrixResult, filename string) error {
 			fmt.Sprintf("%.3f", r.Max),
 			fmt.Sprintf("%.3f", r.P90),
 			fmt.Sprintf("%.3f", r.P95),
+			fmt.Sprintf("%.4f", r.CV),
+			fmt.Sprintf("%.3f", r.MAD),
+		}
+		for _, p := range result.Config.Percentiles {
+			record = append(record, fmt.Sprintf("%.3f", percentileValue(r, p)))
+		}
+		record = append(record,
+			fmt.Sprintf("%.3f", r.MeanCI.Low),
+			fmt.Sprintf("%.3f", r.MeanCI.High),
+			fmt.Sprintf("%.3f", r.MedianCI.Low),
+			fmt.Sprintf("%.3f", r.MedianCI.High),
+			fmt.Sprintf("%.6f", r.Trend.Slope),
+			fmt.Sprintf("%.4f", r.Trend.Drift),
+			fmt.Sprintf("%.4f", r.Trend.P),
 			fmt.Sprintf("%.1f", r.SuccessRate),
 			fmt.Sprintf("%d", r.TotalRuns),
 			fmt.Sprintf("%d", r.SuccessRuns),
+			fmt.Sprintf("%d", r.TimedOut),
+			fmt.Sprintf("%d", r.Outliers),
+			fmt.Sprintf("%d", len(r.NoisyHostSamples())),
+			strings.Join(r.OtherContainers, "; "),
 			r.Error,
-		}
+		)
 		if err := writer.Write(record); err != nil {
 			return err
 		}
@@ -197,6 +204,53 @@ func SaveSummaryMarkdown(result *MatrixResult, filename string) error {
 	md.WriteString(fmt.Sprintf("- **Repository:** %s\n", result.Config.RepoURL))
 	md.WriteString(fmt.Sprintf("- **Command:** `%s`\n", result.Config.Command))
 	md.WriteString(fmt.Sprintf("- **Runs per Config:** %d\n", result.Config.Runs))
+	if result.Config.Timeout > 0 {
+		md.WriteString(fmt.Sprintf("- **Run Timeout:** %s\n", result.Config.Timeout))
+	}
+	if result.Config.Setup != "" {
+		md.WriteString(fmt.Sprintf("- **Setup:** `%s`\n", result.Config.Setup))
+	}
+	if result.Config.Prepare != "" {
+		md.WriteString(fmt.Sprintf("- **Prepare (each run, untimed):** `%s`\n", result.Config.Prepare))
+	}
+	if result.Config.Conclude != "" {
+		md.WriteString(fmt.Sprintf("- **Conclude (each run, untimed):** `%s`\n", result.Config.Conclude))
+	}
+	if result.Config.Cleanup != "" {
+		md.WriteString(fmt.Sprintf("- **Cleanup:** `%s`\n", result.Config.Cleanup))
+	}
+	if result.Config.Service != "" {
+		md.WriteString(fmt.Sprintf("- **Service:** `%s` (ready: %s)\n", result.Config.Service, result.Config.Readiness.Description()))
+	}
+	if result.Config.MeasureStartup {
+		md.WriteString(fmt.Sprintf("- **Measured:** startup until ready (%s)\n", result.Config.Readiness.Description()))
+	}
+	if result.Config.Shell != "" {
+		md.WriteString(fmt.Sprintf("- **Shell:** %s\n", result.Config.Shell))
+	}
+	if result.Config.Cwd != "" {
+		md.WriteString(fmt.Sprintf("- **Working Directory:** `%s`\n", result.Config.Cwd))
+	}
+	if env := formatEnv(result.Config); env != "" {
+		md.WriteString(fmt.Sprintf("- **Environment:** `%s`\n", env))
+	}
+	if len(result.Config.ExpectedExitCodes) > 0 {
+		md.WriteString(fmt.Sprintf("- **Expected Exit Codes:** %s\n", formatIntList(result.Config.ExpectedExitCodes)))
+	}
+	if result.Config.IgnoreFailure {
+		md.WriteString("- **Ignore Failures:** every completed run is timed, whatever its exit code\n")
+	}
+	if result.Config.Retries > 0 {
+		md.WriteString(fmt.Sprintf("- **Retries:** up to %d per failed run\n", result.Config.Retries))
+	}
+	md.WriteString(fmt.Sprintf("- **Minimum Success Rate:** %g%%\n", result.Config.MinSuccessRate))
+	if result.Config.TrimOutliers {
+		md.WriteString("- **Outliers:** excluded from the statistics\n")
+	}
+	if result.Config.RequireQuietHost {
+		md.WriteString("- **Quiet Host:** each run waited for low background load\n")
+	}
+	md.WriteString(fmt.Sprintf("- **Confidence Level:** %s\n", benchmark.FormatConfidence(result.Config.ConfidenceLevel())))
 
 	// Type-specific configuration
 	switch result.Config.Type {
@@ -213,6 +267,8 @@ func SaveSummaryMarkdown(result *MatrixResult, filename string) error {
 
 	if result.Config.SkipWarmup {
 		md.WriteString("- **Warm-up:** Disabled\n")
+	} else if result.Config.Warmup != "" {
+		md.WriteString(fmt.Sprintf("- **Warm-up:** %s (excluded from stats)\n", result.Config.Warmup))
 	} else {
 		md.WriteString("- **Warm-up:** Enabled (excluded from stats)\n")
 	}
@@ -220,23 +276,27 @@ func SaveSummaryMarkdown(result *MatrixResult, filename string) error {
 
 	// Summary table
 	md.WriteString("## Results Summary\n\n")
-	md.WriteString("| CPUs | RAM | Mean | Median | Std Dev | Min | Max | Success Rate |\n")
-	md.WriteString("|------|-----|------|--------|---------|-----|-----|-------------|\n")
+	ciHeader := benchmark.FormatConfidence(result.Config.ConfidenceLevel()) + " CI"
+	md.WriteString(fmt.Sprintf("| CPUs | RAM | Mean | %s | Median | Std Dev | Min | Max | Distribution | Outliers | Success Rate |\n", ciHeader))
+	md.WriteString("|------|-----|------|--------|--------|---------|-----|-----|--------------|----------|-------------|\n")
 
 	for _, r := range result.Results {
-		if r.Success {
-			md.WriteString(fmt.Sprintf("| %d | %d GB | %s | %s | %s | %s | %s | %.0f%% |\n",
+		if r.HasStats() {
+			md.WriteString(fmt.Sprintf("| %d | %d GB | %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
 				r.Config.CPUs,
 				r.Config.Memory,
 				formatDuration(r.Mean),
+				formatInterval(r.MeanCI),
 				formatDuration(r.Median),
 				formatDuration(r.StdDev),
 				formatDuration(r.Min),
 				formatDuration(r.Max),
-				r.SuccessRate,
+				formatSparkline(r),
+				formatOutliers(r),
+				formatSuccessRate(r),
 			))
 		} else {
-			md.WriteString(fmt.Sprintf("| %d | %d GB | FAILED | - | - | - | - | 0%% |\n",
+			md.WriteString(fmt.Sprintf("| %d | %d GB | FAILED | - | - | - | - | - | - | - | 0%% |\n",
 				r.Config.CPUs,
 				r.Config.Memory,
 			))
@@ -249,17 +309,66 @@ func SaveSummaryMarkdown(result *MatrixResult, filename string) error {
 	for _, r := range result.Results {
 		md.WriteString(fmt.Sprintf("### %s\n\n", r.Config.String()))
 
-		if r.Success {
+		if r.HasStats() {
+			if !r.Success {
+				md.WriteString(fmt.Sprintf("**Status:** Failed (%s)\n\n", r.Error))
+			}
 			md.WriteString("| Metric | Value |\n")
 			md.WriteString("|--------|-------|\n")
 			md.WriteString(fmt.Sprintf("| Mean | %s (%.3fs) |\n", formatDuration(r.Mean), r.Mean))
+			if r.MeanCI.Valid() {
+				level := benchmark.FormatConfidence(r.Confidence)
+				md.WriteString(fmt.Sprintf("| Mean %s CI (t) | %s |\n", level, formatInterval(r.MeanCI)))
+				md.WriteString(fmt.Sprintf("| Mean %s CI (bootstrap) | %s |\n", level, formatInterval(r.MeanBootstrapCI)))
+			}
 			md.WriteString(fmt.Sprintf("| Median | %s (%.3fs) |\n", formatDuration(r.Median), r.Median))
+			if r.MedianCI.Valid() {
+				md.WriteString(fmt.Sprintf("| Median %s CI (bootstrap) | %s |\n", benchmark.FormatConfidence(r.Confidence), formatInterval(r.MedianCI)))
+			}
 			md.WriteString(fmt.Sprintf("| Std Dev | %s (%.3fs) |\n", formatDuration(r.StdDev), r.StdDev))
+			md.WriteString(fmt.Sprintf("| CV | %.2f%% |\n", r.CV*100))
+			md.WriteString(fmt.Sprintf("| MAD | %s (%.3fs) |\n", formatDuration(r.MAD), r.MAD))
 			md.WriteString(fmt.Sprintf("| Min | %s (%.3fs) |\n", formatDuration(r.Min), r.Min))
 			md.WriteString(fmt.Sprintf("| Max | %s (%.3fs) |\n", formatDuration(r.Max), r.Max))
 			md.WriteString(fmt.Sprintf("| P90 | %s (%.3fs) |\n", formatDuration(r.P90), r.P90))
 			md.WriteString(fmt.Sprintf("| P95 | %s (%.3fs) |\n", formatDuration(r.P95), r.P95))
+			for _, p := range r.Percentiles {
+				md.WriteString(fmt.Sprintf("| %s | %s (%.3fs) |\n", p.Label(), formatDuration(p.Value), p.Value))
+			}
 			md.WriteString(fmt.Sprintf("| Success Rate | %.1f%% (%d/%d) |\n", r.SuccessRate, r.SuccessRuns, r.TotalRuns))
+			if r.TimedOut > 0 {
+				md.WriteString(fmt.Sprintf("| Timed Out Runs | %d |\n", r.TimedOut))
+			}
+			if r.HookFailures > 0 {
+				md.WriteString(fmt.Sprintf("| Hook Failures | %d |\n", r.HookFailures))
+			}
+			if r.Outliers > 0 {
+				md.WriteString(fmt.Sprintf("| Outliers | %s |\n", formatOutliers(r)))
+			}
+			if r.Trend.Valid() {
+				md.WriteString(fmt.Sprintf("| Trend | %s |\n", r.Trend))
+			}
+			if len(r.HostSamples) > 0 {
+				md.WriteString(fmt.Sprintf("| Host Noise | %d of %d samples noisy |\n", len(r.NoisyHostSamples()), len(r.HostSamples)))
+			}
+			if len(r.OtherContainers) > 0 {
+				md.WriteString(fmt.Sprintf("| Other Containers | %s |\n", strings.Join(r.OtherContainers, ", ")))
+			}
+			if isNoisy(r) {
+				md.WriteString(fmt.Sprintf("\n> ⚠ The host was busy: %s. The runs may be slower than usual.\n", hostNoiseSummary(r)))
+			}
+			if r.Trend.Drifting() {
+				md.WriteString(fmt.Sprintf("\n> ⚠ %s\n", benchmark.DriftWarning(r.Trend)))
+			}
+			if len(r.Durations) > 1 {
+				histogram := benchmark.NewHistogram(r.Durations)
+				md.WriteString("\n```\n")
+				md.WriteString(histogram.String())
+				md.WriteString("```\n")
+				if histogram.MultiModal() {
+					md.WriteString(fmt.Sprintf("\n> ⚠ %s\n", benchmark.MultiModalWarning(histogram)))
+				}
+			}
 		} else {
 			md.WriteString(fmt.Sprintf("**Status:** Failed\n\n"))
 			md.WriteString(fmt.Sprintf("**Error:** %s\n", r.Error))
@@ -268,17 +377,18 @@ func SaveSummaryMarkdown(result *MatrixResult, filename string) error {
 	}
 
 	// Failed configurations section if any
-	var failed []ConfigResult
-	for _, r := range result.Results {
-		if !r.Success {
-			failed = append(failed, r)
-		}
-	}
-
+	failed := failedConfigs(result)
 	if len(failed) > 0 {
 		md.WriteString("## Failed Configurations\n\n")
 		for _, r := range failed {
-			md.WriteString(fmt.Sprintf("- **%s:** %s\n", r.Config.String(), r.Error))
+			md.WriteString(fmt.Sprintf("- **%s:** %s\n", r.Config.String(), failureSummary(r)))
+			if r.FailureExcerpt != "" {
+				md.WriteString("\n  ```\n")
+				for _, line := range strings.Split(r.FailureExcerpt, "\n") {
+					md.WriteString(fmt.Sprintf("  %s\n", line))
+				}
+				md.WriteString("  ```\n")
+			}
 		}
 		md.WriteString("\n")
 	}
@@ -292,9 +402,143 @@ func SaveSummaryMarkdown(result *MatrixResult, filename string) error {
 	return err
 }
 
+// failedConfigs returns the configurations that failed outright or had failed runs
+func failedConfigs(result *MatrixResult) []ConfigResult {
+	var failed []ConfigResult
+	for _, r := range result.Results {
+		if !r.Success || r.SuccessRuns < r.TotalRuns {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// driftingConfigs returns the configurations whose runs got systematically slower or faster
+func driftingConfigs(result *MatrixResult) []ConfigResult {
+	var drifting []ConfigResult
+	for _, r := range result.Results {
+		if r.HasStats() && r.Trend.Drifting() {
+			drifting = append(drifting, r)
+		}
+	}
+	return drifting
+}
+
+// noisyConfigs returns the configurations measured while the host was busy
+func noisyConfigs(result *MatrixResult) []ConfigResult {
+	var noisy []ConfigResult
+	for _, r := range result.Results {
+		if isNoisy(r) {
+			noisy = append(noisy, r)
+		}
+	}
+	return noisy
+}
+
+// isNoisy reports whether background load or other containers may have slowed a configuration down
+func isNoisy(r ConfigResult) bool {
+	return len(r.NoisyHostSamples()) > 0 || len(r.OtherContainers) > 0
+}
+
+// hostNoiseSummary describes the host noise of a configuration, e.g.
+// "2 of 11 host samples noisy (the hypervisor stole 9% of CPU time), other
+// containers: db (postgres:16)"
+func hostNoiseSummary(r ConfigResult) string {
+	var parts []string
+	if noisy := r.NoisyHostSamples(); len(noisy) > 0 {
+		parts = append(parts, fmt.Sprintf("%d of %d host samples noisy (%s)", len(noisy), len(r.HostSamples), noisy[0].Description()))
+	}
+	if len(r.OtherContainers) > 0 {
+		parts = append(parts, "other containers: "+strings.Join(r.OtherContainers, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hostSamplesJSON returns the host samples, or an empty list rather than null
+func hostSamplesJSON(samples []benchmark.HostSample) []benchmark.HostSample {
+	if samples == nil {
+		return []benchmark.HostSample{}
+	}
+	return samples
+}
+
+// otherContainersJSON returns the other containers, or an empty list rather than null
+func otherContainersJSON(containers []string) []string {
+	if containers == nil {
+		return []string{}
+	}
+	return containers
+}
+
+// failureSummary describes why a configuration is listed as failed
+func failureSummary(r ConfigResult) string {
+	summary := fmt.Sprintf("%d of %d runs failed", r.TotalRuns-r.SuccessRuns, r.TotalRuns)
+	if !r.Success {
+		if !r.HasStats() {
+			return r.Error
+		}
+		summary = r.Error + ", " + summary
+	}
+
+	if r.FailedRun > 0 {
+		summary += fmt.Sprintf(" (last: run %d, exit code %d)", r.FailedRun, r.FailedExitCode)
+	}
+	return summary
+}
+
+// formatOutliers formats the outlier count, noting when they were excluded from the statistics
+func formatOutliers(r ConfigResult) string {
+	if r.Trimmed {
+		return fmt.Sprintf("%d (excluded)", r.Outliers)
+	}
+	return fmt.Sprintf("%d", r.Outliers)
+}
+
+// percentileValue returns the requested percentile p of r (0 if it was not reported)
+func percentileValue(r ConfigResult, p float64) float64 {
+	for _, value := range r.Percentiles {
+		if value.Percentile == p {
+			return value.Value
+		}
+	}
+	return 0
+}
+
+// formatInterval formats a confidence interval as a range, e.g. "1.1s–1.3s"
+func formatInterval(ci benchmark.ConfidenceInterval) string {
+	if !ci.Valid() {
+		return "-"
+	}
+	return formatDuration(ci.Low) + "–" + formatDuration(ci.High)
+}
+
+// formatSuccessRate formats the success rate, marking configurations that failed the policy
+func formatSuccessRate(r ConfigResult) string {
+	if !r.Success {
+		return fmt.Sprintf("%.0f%% ✗", r.SuccessRate)
+	}
+	return fmt.Sprintf("%.0f%%", r.SuccessRate)
+}
+
 // generateGraphsMarkdown generates ASCII graphs as markdown code blocks
 func generateGraphsMarkdown(result *MatrixResult) string {
 	var sb strings.Builder
+	for _, graph := range generateGraphStrings(result) {
+		sb.WriteString("```\n")
+		sb.WriteString(graph)
+		sb.WriteString("```\n\n")
+	}
+	return sb.String()
+}
+
+// generateGraphStrings generates the ASCII graphs that suit the benchmark type
+func generateGraphStrings(result *MatrixResult) []string {
+	var graphs []string
+	add := func(graph string) {
+		if graph != "" {
+			graphs = append(graphs, graph)
+		}
+	}
 
 	switch result.Config.Type {
 	case BenchmarkTypeAll:
@@ -320,51 +564,26 @@ func generateGraphsMarkdown(result *MatrixResult) string {
 
 		// CPU sweep graphs
 		for _, ram := range rams {
-			graph := generateCPUSweepGraphString(result, ram)
-			if graph != "" {
-				sb.WriteString("```\n")
-				sb.WriteString(graph)
-				sb.WriteString("```\n\n")
-			}
+			add(generateCPUSweepGraphString(result, ram))
 		}
 
 		// RAM sweep graphs
 		for _, cpu := range cpus {
-			graph := generateRAMSweepGraphString(result, cpu)
-			if graph != "" {
-				sb.WriteString("```\n")
-				sb.WriteString(graph)
-				sb.WriteString("```\n\n")
-			}
+			add(generateRAMSweepGraphString(result, cpu))
 		}
 
 	case BenchmarkTypeSweepCPU:
-		graph := generateCPUSweepGraphString(result, result.Config.FixedRAM)
-		if graph != "" {
-			sb.WriteString("```\n")
-			sb.WriteString(graph)
-			sb.WriteString("```\n\n")
-		}
+		add(generateCPUSweepGraphString(result, result.Config.FixedRAM))
 
 	case BenchmarkTypeSweepRAM:
-		graph := generateRAMSweepGraphString(result, result.Config.FixedCPU)
-		if graph != "" {
-			sb.WriteString("```\n")
-			sb.WriteString(graph)
-			sb.WriteString("```\n\n")
-		}
+		add(generateRAMSweepGraphString(result, result.Config.FixedCPU))
 
 	default:
 		// Custom mode - show generic graph
-		graph := generateGenericGraphString(result)
-		if graph != "" {
-			sb.WriteString("```\n")
-			sb.WriteString(graph)
-			sb.WriteString("```\n\n")
-		}
+		add(generateGenericGraphString(result))
 	}
 
-	return sb.String()
+	return graphs
 }
 
 // generateCPUSweepGraphString generates a graph string for CPU sweep at fixed RAM
@@ -428,23 +647,13 @@ func generateBarChartString(results []ConfigResult, title string, labelType stri
 	sb.WriteString(fmt.Sprintf("%s\n", title))
 	sb.WriteString(fmt.Sprintf("%s\n\n", strings.Repeat("=", len(title))))
 
-	// Find max mean time for scaling
-	maxMean := 0.0
-	for _, r := range results {
-		if r.Mean > maxMean {
-			maxMean = r.Mean
-		}
-	}
+	// Scale to the largest mean or upper confidence bound
+	maxMean := chartScale(results)
 
 	graphWidth := 50
 
 	for _, r := range results {
-		barWidth := int((r.Mean / maxMean) * float64(graphWidth))
-		if barWidth < 1 {
-			barWidth = 1
-		}
-
-		bar := strings.Repeat("█", barWidth)
+		bar := errorBar(r, maxMean, graphWidth)
 
 		var label string
 		switch labelType {
@@ -456,7 +665,7 @@ func generateBarChartString(results []ConfigResult, title string, labelType stri
 			label = fmt.Sprintf("%2d CPU %2d GB", r.Config.CPUs, r.Config.Memory)
 		}
 
-		timeLabel := formatDuration(r.Mean)
+		timeLabel := formatMeanWithCI(r)
 		sb.WriteString(fmt.Sprintf("%s │%s %s\n", label, bar, timeLabel))
 	}
 
@@ -467,6 +676,9 @@ func generateBarChartString(results []ConfigResult, title string, labelType stri
 	sb.WriteString(fmt.Sprintf("        %s%s\n",
 		strings.Repeat(" ", graphWidth-len(formatDuration(maxMean))+8),
 		formatDuration(maxMean)))
+	if legend := errorBarLegend(results); legend != "" {
+		sb.WriteString(fmt.Sprintf("        %s\n", legend))
+	}
 	sb.WriteString("\n")
 
 	return sb.String()
@@ -543,27 +755,16 @@ func PrintBuildTimeGraph(result *MatrixResult) {
 	fmt.Printf("%s\n", title)
 	fmt.Printf("%s\n\n", strings.Repeat("=", len(title)))
 
-	// Find max mean time for scaling
-	maxMean := 0.0
-	for _, r := range successful {
-		if r.Mean > maxMean {
-			maxMean = r.Mean
-		}
-	}
+	// Scale to the largest mean or upper confidence bound
+	maxMean := chartScale(successful)
 
 	// Graph parameters
 	graphWidth := 50 // Width of the bar area in characters
 
 	// Print each bar
 	for _, r := range successful {
-		// Calculate bar width proportional to mean time
-		barWidth := int((r.Mean / maxMean) * float64(graphWidth))
-		if barWidth < 1 {
-			barWidth = 1
-		}
-
-		// Create the bar
-		bar := strings.Repeat("█", barWidth)
+		// Create the bar, proportional to mean time, with its confidence interval
+		bar := errorBar(r, maxMean, graphWidth)
 
 		// Format label based on benchmark type
 		var label string
@@ -577,7 +778,7 @@ func PrintBuildTimeGraph(result *MatrixResult) {
 		}
 
 		// Format time label
-		timeLabel := formatDuration(r.Mean)
+		timeLabel := formatMeanWithCI(r)
 
 		// Print the row
 		fmt.Printf("%s │%s %s\n", label, bar, timeLabel)
@@ -591,6 +792,9 @@ func PrintBuildTimeGraph(result *MatrixResult) {
 	fmt.Printf("        %s%s\n",
 		strings.Repeat(" ", graphWidth-len(formatDuration(maxMean))+8),
 		formatDuration(maxMean))
+	if legend := errorBarLegend(successful); legend != "" {
+		fmt.Printf("        %s\n", legend)
+	}
 
 	fmt.Printf("\n")
 }
@@ -684,25 +888,15 @@ func printBarChart(results []ConfigResult, labelType string) {
 		return
 	}
 
-	// Find max mean time for scaling
-	maxMean := 0.0
-	for _, r := range results {
-		if r.Mean > maxMean {
-			maxMean = r.Mean
-		}
-	}
+	// Scale to the largest mean or upper confidence bound
+	maxMean := chartScale(results)
 
 	// Graph parameters
 	graphWidth := 50
 
 	// Print each bar
 	for _, r := range results {
-		barWidth := int((r.Mean / maxMean) * float64(graphWidth))
-		if barWidth < 1 {
-			barWidth = 1
-		}
-
-		bar := strings.Repeat("█", barWidth)
+		bar := errorBar(r, maxMean, graphWidth)
 
 		var label string
 		if labelType == "cpu" {
@@ -711,7 +905,7 @@ func printBarChart(results []ConfigResult, labelType string) {
 			label = fmt.Sprintf("%2d GB", r.Config.Memory)
 		}
 
-		timeLabel := formatDuration(r.Mean)
+		timeLabel := formatMeanWithCI(r)
 		fmt.Printf("%s │%s %s\n", label, bar, timeLabel)
 	}
 
@@ -723,10 +917,73 @@ func printBarChart(results []ConfigResult, labelType string) {
 	fmt.Printf("        %s%s\n",
 		strings.Repeat(" ", graphWidth-len(formatDuration(maxMean))+8),
 		formatDuration(maxMean))
+	if legend := errorBarLegend(results); legend != "" {
+		fmt.Printf("        %s\n", legend)
+	}
 
 	fmt.Printf("\n")
 }
 
+// chartScale returns the value drawn at full width: the largest mean, or the
+// largest upper confidence bound so that every error range fits
+func chartScale(results []ConfigResult) float64 {
+	scale := 0.0
+	for _, r := range results {
+		scale = max(scale, r.Mean, r.MeanCI.High)
+	}
+	return scale
+}
+
+// errorBar draws the mean of r as a bar of up to width characters at scale,
+// with the confidence interval of the mean as an error range: the bar is
+// shaded from the lower bound to the mean, and a whisker reaches the upper bound
+func errorBar(r ConfigResult, scale float64, width int) string {
+	position := func(seconds float64) int {
+		return int((seconds / scale) * float64(width))
+	}
+
+	if !r.MeanCI.Valid() {
+		return benchmark.Bar(r.Mean, scale, width)
+	}
+
+	end := max(position(r.Mean), 1)
+
+	low := min(max(position(r.MeanCI.Low), 0), end)
+	high := max(position(r.MeanCI.High), end)
+	return strings.Repeat("█", low) + strings.Repeat("▒", end-low) + strings.Repeat("─", high-end) + "┤"
+}
+
+// errorBarLegend explains the error ranges drawn by errorBar (empty when none was drawn)
+func errorBarLegend(results []ConfigResult) string {
+	for _, r := range results {
+		if r.MeanCI.Valid() {
+			return fmt.Sprintf("▒─┤ %s confidence interval of the mean", benchmark.FormatConfidence(r.Confidence))
+		}
+	}
+	return ""
+}
+
+// formatSparkline draws the distribution of the durations of r as a sparkline,
+// marked when it looks multi-modal ("-" with fewer than two durations)
+func formatSparkline(r ConfigResult) string {
+	if len(r.Durations) < 2 {
+		return "-"
+	}
+	histogram := benchmark.NewHistogram(r.Durations)
+	if histogram.MultiModal() {
+		return histogram.Sparkline() + " ⚠ multi-modal"
+	}
+	return histogram.Sparkline()
+}
+
+// formatMeanWithCI formats the mean followed by its confidence interval, e.g. "1.2s [1.1s–1.3s]"
+func formatMeanWithCI(r ConfigResult) string {
+	if !r.MeanCI.Valid() {
+		return formatDuration(r.Mean)
+	}
+	return fmt.Sprintf("%s [%s]", formatDuration(r.Mean), formatInterval(r.MeanCI))
+}
+
 // sortInts sorts a slice of ints in ascending order
 func sortInts(a []int) {
 	for i := 0; i < len(a)-1; i++ {
@@ -737,3 +994,153 @@ func sortInts(a []int) {
 		}
 	}
 }
+
+// formatEnv describes the environment customisation of the command, e.g.
+// "clean, RUSTFLAGS=-C target-cpu=native", with secret values redacted
+func formatEnv(config Config) string {
+	var parts []string
+	if config.CleanEnv {
+		parts = append(parts, "clean")
+	}
+	redacted := benchmark.RedactEnv(config.Env)
+	names := make([]string, 0, len(redacted))
+	for name := range redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, name+"="+redacted[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PrintDiff prints the configuration-by-configuration comparison of two matrix benchmarks
+func PrintDiff(diff *MatrixDiff) {
+	fmt.Printf("\n")
+	fmt.Printf("Matrix Comparison\n")
+	fmt.Printf("=================\n\n")
+	fmt.Printf("Baseline:  %s\n", diff.Baseline)
+	fmt.Printf("Candidate: %s\n\n", diff.Candidate)
+
+	ciHeader := benchmark.FormatConfidence(diff.Confidence) + " CI"
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "CPUs\tRAM\tBaseline\tCandidate\tDifference\t%s\tp (Welch)\tVerdict\n", ciHeader)
+	fmt.Fprintf(w, "----\t---\t--------\t---------\t----------\t%s\t---------\t-------\n", strings.Repeat("-", len(ciHeader)))
+	for _, d := range diff.Configs {
+		baseline, candidate, difference, ci, p, verdict := diffCells(d)
+		fmt.Fprintf(w, "%d\t%d GB\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			d.Config.CPUs, d.Config.Memory, baseline, candidate, difference, ci, p, verdict)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%s\n", diffSummary(diff))
+}
+
+// SaveDiffJSON saves the comparison of two matrix benchmarks as JSON
+func SaveDiffJSON(diff *MatrixDiff, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewDiffDocument(diff))
+}
+
+// SaveDiffMarkdown saves the comparison of two matrix benchmarks as a Markdown report
+func SaveDiffMarkdown(diff *MatrixDiff, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var md strings.Builder
+
+	md.WriteString("# Matrix Comparison Report\n\n")
+	md.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format(time.RFC1123)))
+	md.WriteString(fmt.Sprintf("- **Baseline:** `%s`\n", diff.Baseline))
+	md.WriteString(fmt.Sprintf("- **Candidate:** `%s`\n\n", diff.Candidate))
+
+	md.WriteString("## Results\n\n")
+	md.WriteString(fmt.Sprintf("| CPUs | RAM | Baseline | Candidate | Difference | %s CI | p (Welch) | Verdict |\n", benchmark.FormatConfidence(diff.Confidence)))
+	md.WriteString("|------|-----|----------|-----------|------------|--------|-----------|---------|\n")
+	for _, d := range diff.Configs {
+		baseline, candidate, difference, ci, p, verdict := diffCells(d)
+		md.WriteString(fmt.Sprintf("| %d | %d GB | %s | %s | %s | %s | %s | %s |\n",
+			d.Config.CPUs, d.Config.Memory, baseline, candidate, difference, ci, p, verdict))
+	}
+	md.WriteString("\n")
+
+	md.WriteString(fmt.Sprintf("%s\n\n", diffSummary(diff)))
+	md.WriteString("Matrix summaries hold the statistics of each configuration but not its runs, so configurations are compared with Welch's t-test only.\n")
+
+	_, err = file.WriteString(md.String())
+	return err
+}
+
+// diffCells formats the table cells of one configuration of a matrix comparison
+func diffCells(d ConfigDiff) (baseline, candidate, difference, ci, p, verdict string) {
+	baseline, candidate, difference, ci, p = "-", "-", "-", "-", "-"
+	if d.Baseline != nil && d.Baseline.HasStats() {
+		baseline = formatDuration(d.Baseline.Mean)
+	}
+	if d.Candidate != nil && d.Candidate.HasStats() {
+		candidate = formatDuration(d.Candidate.Mean)
+	}
+
+	switch {
+	case d.Candidate == nil:
+		return baseline, candidate, difference, ci, p, "only in baseline"
+	case d.Baseline == nil:
+		return baseline, candidate, difference, ci, p, "only in candidate"
+	case d.Diff.Verdict == benchmark.VerdictInsufficient:
+		return baseline, candidate, difference, ci, p, string(d.Diff.Verdict)
+	}
+
+	difference = fmt.Sprintf("%s (%+.1f%%)", formatSignedDuration(d.Diff.Difference), d.Diff.RelativeDifference*100)
+	ci = formatSignedDuration(d.Diff.DifferenceCI.Low) + " … " + formatSignedDuration(d.Diff.DifferenceCI.High)
+	p = strings.TrimPrefix(benchmark.FormatPValue(d.Diff.WelchP), "= ")
+	return baseline, candidate, difference, ci, p, string(d.Diff.Verdict)
+}
+
+// diffSummary counts the verdicts of a matrix comparison, e.g. "2 faster, 1 slower, 3 no significant change"
+func diffSummary(diff *MatrixDiff) string {
+	counts := make(map[benchmark.Verdict]int)
+	unmatched := 0
+	for _, d := range diff.Configs {
+		if !d.Matched() {
+			unmatched++
+			continue
+		}
+		counts[d.Diff.Verdict]++
+	}
+
+	var parts []string
+	for _, verdict := range []benchmark.Verdict{benchmark.VerdictFaster, benchmark.VerdictSlower, benchmark.VerdictNoChange, benchmark.VerdictInsufficient} {
+		if counts[verdict] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[verdict], verdict))
+		}
+	}
+	if unmatched > 0 {
+		parts = append(parts, fmt.Sprintf("%d only in one summary", unmatched))
+	}
+	if len(parts) == 0 {
+		return "No configurations to compare."
+	}
+	return fmt.Sprintf("Candidate vs baseline at %s confidence: %s.", benchmark.FormatConfidence(diff.Confidence), strings.Join(parts, ", "))
+}
+
+// formatSignedDuration formats a duration difference with an explicit sign
+func formatSignedDuration(seconds float64) string {
+	switch {
+	case seconds > 0:
+		return "+" + formatDuration(seconds)
+	case seconds < 0:
+		return "-" + formatDuration(-seconds)
+	default:
+		return "0s"
+	}
+}
@@ -2,14 +2,16 @@ package matrix
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/attunehq/caliper/benchmark"
 )
 
 // Run executes the matrix benchmark with all configurations sequentially
@@ -58,6 +60,33 @@ func Run(ctx context.Context, config Config, binaryPath string) (*MatrixResult,
 	fmt.Printf("Command:    %s\n", config.Command)
 	fmt.Printf("Runs:       %d per configuration\n", config.Runs)
 	fmt.Printf("Configs:    %d configurations\n", len(config.Configs))
+	if config.Timeout > 0 {
+		fmt.Printf("Timeout:    %s per run\n", config.Timeout)
+	}
+	if config.Prepare != "" {
+		fmt.Printf("Prepare:    %s\n", config.Prepare)
+	}
+	if config.Shell != "" {
+		fmt.Printf("Shell:      %s\n", config.Shell)
+	}
+	if config.Service != "" {
+		fmt.Printf("Service:    %s (ready: %s)\n", config.Service, config.Readiness.Description())
+	}
+	if config.MeasureStartup {
+		fmt.Printf("Measured:   startup until ready (%s)\n", config.Readiness.Description())
+	}
+	if config.Cwd != "" {
+		fmt.Printf("Cwd:        %s\n", config.Cwd)
+	}
+	if env := formatEnv(config); env != "" {
+		fmt.Printf("Env:        %s\n", env)
+	}
+	if config.MinSuccessRate < 100 {
+		fmt.Printf("Min Success Rate: %g%%\n", config.MinSuccessRate)
+	}
+	if config.Confidence != "" {
+		fmt.Printf("Confidence: %s\n", benchmark.FormatConfidence(config.ConfidenceLevel()))
+	}
 	if config.Debug {
 		fmt.Printf("Debug:      enabled\n")
 	}
@@ -121,6 +150,7 @@ func runSingleConfig(
 		CPUs:      resourceCfg.CPUs,
 		Memory:    resourceCfg.Memory,
 		MountPath: workspaceDir,
+		Shell:     config.Shell,
 	}, debug)
 	if err != nil {
 		result.Error = fmt.Sprintf("failed to create container: %v", err)
@@ -139,6 +169,15 @@ func runSingleConfig(
 
 	fmt.Printf("  Container started: %s\n", container.ID[:12])
 
+	// Other containers compete with the benchmark for the host's CPUs, disks and memory
+	others, err := dockerClient.OtherContainers(ctx, container.ID)
+	if err != nil {
+		debugLog(debug, "Failed to list other containers: %v", err)
+	} else if len(others) > 0 {
+		fmt.Printf("  ⚠ Other containers running on the Docker daemon: %s\n", strings.Join(others, ", "))
+		result.OtherContainers = others
+	}
+
 	// Clone repository
 	fmt.Printf("  Cloning repository: %s\n", config.RepoURL)
 	cloneCmd := fmt.Sprintf("git clone --depth 1 %s /workspace/repo", config.RepoURL)
@@ -178,23 +217,7 @@ func runSingleConfig(
 	// Construct benchmark command (prefix with repo name)
 	repoName := config.RepoName()
 	benchmarkName := fmt.Sprintf("%s_%s", repoName, resourceCfg.DirName())
-	warmupFlag := ""
-	if config.SkipWarmup {
-		warmupFlag = "--no-warmup"
-	}
-	debugFlag := ""
-	if debug {
-		debugFlag = "--debug"
-	}
-
-	benchmarkCmd := fmt.Sprintf(
-		"/workspace/caliper --runs %d --command %q --output-dir /workspace/results --name %s %s %s",
-		config.Runs,
-		config.Command,
-		benchmarkName,
-		warmupFlag,
-		debugFlag,
-	)
+	benchmarkCmd := buildBenchmarkCommand(config, benchmarkName)
 
 	fmt.Printf("  Running benchmark: %s\n", config.Command)
 	fmt.Printf("  Number of runs: %d\n", config.Runs)
@@ -248,57 +271,196 @@ func runSingleConfig(
 		debugLog(debug, "JSON parse error: %v", err)
 		if benchResult.ExitCode != 0 {
 			result.Error = fmt.Sprintf("benchmark failed (exit code %d)", benchResult.ExitCode)
+			// No results were written (e.g. the warm-up failed), so keep the end of caliper's own output
+			result.FailureExcerpt = lastLines(benchResult.Stdout+benchResult.Stderr, failureExcerptLines)
 			return result
 		}
 	}
 
+	// Apply the failure policy: a configuration with a few failed runs still has
+	// statistics, but only counts as successful above the minimum success rate
+	if result.SuccessRuns == 0 {
+		result.Error = "no successful runs"
+		return result
+	}
+	if result.SuccessRate < config.MinSuccessRate {
+		result.Error = fmt.Sprintf("success rate %.1f%% is below the minimum of %.1f%%", result.SuccessRate, config.MinSuccessRate)
+		return result
+	}
+	if result.HookFailures > 0 {
+		result.Error = fmt.Sprintf("%d hook failure(s)", result.HookFailures)
+		return result
+	}
+
 	result.Success = true
 	return result
 }
 
+// buildBenchmarkCommand constructs the in-container caliper invocation for one configuration
+func buildBenchmarkCommand(config Config, benchmarkName string) string {
+	args := []string{
+		"/workspace/caliper",
+		"--runs", fmt.Sprintf("%d", config.Runs),
+		"--command", shellQuote(config.Command),
+		"--output-dir", "/workspace/results",
+		"--name", benchmarkName,
+	}
+	if config.SkipWarmup {
+		args = append(args, "--no-warmup")
+	} else if config.Warmup != "" {
+		args = append(args, "--warmup", shellQuote(config.Warmup))
+	}
+	if config.Debug {
+		args = append(args, "--debug")
+	}
+	if config.Shell != "" {
+		args = append(args, "--shell", shellQuote(config.Shell))
+	}
+	if config.Calibrate {
+		args = append(args, "--calibrate")
+	}
+	for _, entry := range config.Env {
+		args = append(args, "--env", shellQuote(entry))
+	}
+	if config.CleanEnv {
+		args = append(args, "--clean-env")
+	}
+	if config.Cwd != "" {
+		args = append(args, "--cwd", shellQuote(config.Cwd))
+	}
+	if config.Timeout > 0 {
+		args = append(args, "--timeout", config.Timeout.String())
+	}
+	for _, code := range config.ExpectedExitCodes {
+		args = append(args, "--expected-exit-code", fmt.Sprintf("%d", code))
+	}
+	if config.IgnoreFailure {
+		args = append(args, "--ignore-failure")
+	}
+	if config.Retries > 0 {
+		args = append(args, "--retries", fmt.Sprintf("%d", config.Retries))
+	}
+	for _, code := range config.RetryOnExitCodes {
+		args = append(args, "--retry-on-exit-code", fmt.Sprintf("%d", code))
+	}
+
+	if config.Service != "" {
+		args = append(args, "--service", shellQuote(config.Service))
+	}
+	if config.Readiness.TCP != "" {
+		args = append(args, "--ready-tcp", shellQuote(config.Readiness.TCP))
+	}
+	if config.Readiness.HTTP != "" {
+		args = append(args, "--ready-http", shellQuote(config.Readiness.HTTP))
+	}
+	if config.Readiness.Output != "" {
+		args = append(args, "--ready-output", shellQuote(config.Readiness.Output))
+	}
+	if config.Readiness.Timeout > 0 && config.Readiness.Timeout != benchmark.DefaultReadyTimeout {
+		args = append(args, "--ready-timeout", config.Readiness.Timeout.String())
+	}
+	if config.MeasureStartup {
+		args = append(args, "--measure-startup")
+	}
+
+	if config.OutlierMethod != "" {
+		args = append(args, "--outlier-method", shellQuote(config.OutlierMethod))
+	}
+	if config.TrimOutliers {
+		args = append(args, "--trim-outliers")
+	}
+	if config.Confidence != "" {
+		args = append(args, "--confidence", shellQuote(config.Confidence))
+	}
+	if config.RequireQuietHost {
+		args = append(args, "--require-quiet-host")
+	}
+	if len(config.Percentiles) > 0 {
+		percentiles := make([]string, len(config.Percentiles))
+		for i, p := range config.Percentiles {
+			percentiles[i] = strconv.FormatFloat(p, 'f', -1, 64)
+		}
+		args = append(args, "--percentiles", strings.Join(percentiles, ","))
+	}
+
+	hooks := []struct{ flag, command string }{
+		{"--setup", config.Setup},
+		{"--prepare", config.Prepare},
+		{"--conclude", config.Conclude},
+		{"--cleanup", config.Cleanup},
+	}
+	for _, hook := range hooks {
+		if hook.command != "" {
+			args = append(args, hook.flag, shellQuote(hook.command))
+		}
+	}
+
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes a string for safe use as a single shell word.
+// Single quotes prevent the container shell from expanding $VARS or
+// backticks before the in-container caliper sees the command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // parseResultsJSON reads the benchmark JSON file and extracts statistics
 func parseResultsJSON(jsonPath string, result *ConfigResult) error {
-	data, err := os.ReadFile(jsonPath)
+	r, err := benchmark.LoadResult(jsonPath)
 	if err != nil {
-		return fmt.Errorf("failed to read JSON file: %w", err)
-	}
-
-	var jsonResult struct {
-		Summary struct {
-			TotalRuns   int     `json:"totalRuns"`
-			Successful  int     `json:"successful"`
-			SuccessRate float64 `json:"successRate"`
-		} `json:"summary"`
-		Statistics struct {
-			N      int     `json:"n"`
-			Mean   float64 `json:"mean"`
-			Median float64 `json:"median"`
-			StdDev float64 `json:"stdDev"`
-			Min    float64 `json:"min"`
-			Max    float64 `json:"max"`
-			P90    float64 `json:"p90"`
-			P95    float64 `json:"p95"`
-		} `json:"statistics"`
-	}
-
-	if err := json.Unmarshal(data, &jsonResult); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	result.TotalRuns = jsonResult.Summary.TotalRuns
-	result.SuccessRuns = jsonResult.Summary.Successful
-	result.SuccessRate = jsonResult.Summary.SuccessRate
-	result.Mean = jsonResult.Statistics.Mean
-	result.Median = jsonResult.Statistics.Median
-	result.StdDev = jsonResult.Statistics.StdDev
-	result.Min = jsonResult.Statistics.Min
-	result.Max = jsonResult.Statistics.Max
-	result.P90 = jsonResult.Statistics.P90
-	result.P95 = jsonResult.Statistics.P95
+		return err
+	}
+
+	stats := r.Stats
+	result.TotalRuns = len(r.Runs)
+	result.SuccessRuns = r.SuccessfulRuns()
+	result.SuccessRate = r.SuccessRate
+	result.TimedOut = r.TimedOutRuns()
+	result.HookFailures = len(r.HookFailures)
+	result.Mean = stats.Mean
+	result.Median = stats.Median
+	result.StdDev = stats.StdDev
+	result.CV = stats.CV
+	result.MAD = stats.MAD
+	result.Min = stats.Min
+	result.Max = stats.Max
+	result.P90 = stats.P90
+	result.P95 = stats.P95
+	result.Percentiles = stats.Percentiles
+	result.Outliers = stats.Outliers
+	result.Trimmed = stats.Trimmed
+	result.Confidence = stats.Confidence
+	result.MeanCI = stats.MeanCI
+	result.MeanBootstrapCI = stats.MeanBootstrapCI
+	result.MedianCI = stats.MedianCI
+	result.Trend = stats.Trend
+	result.HostSamples = r.HostSamples
+	result.Durations = r.Durations()
+
+	for _, run := range r.Runs {
+		if !run.Success {
+			result.FailedRun = run.RunNumber
+			result.FailedExitCode = run.ExitCode
+			result.FailureExcerpt = run.StderrTail
+		}
+	}
 
 	return nil
 }
 
+// failureExcerptLines is the number of output lines kept when a configuration fails without results
+const failureExcerptLines = 10
+
+// lastLines returns at most n trailing lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
 // BuildStaticBinary builds a static binary for Linux that can run in Docker containers
 func BuildStaticBinary(outputPath string) error {
 	fmt.Printf("Building static binary for Linux...\n")
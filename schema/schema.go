@@ -0,0 +1,302 @@
+// Package schema holds the published JSON Schemas of caliper's result
+// documents and validates documents against them.
+//
+// The validator supports the subset of JSON Schema (draft 2020-12) the
+// schemas use: type, const, enum, required, properties,
+// additionalProperties, items, minimum, maximum and $ref to $defs, also
+// across the schema files.
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema file names
+const (
+	Result        = "result.schema.json"         // Single benchmark result (caliper run)
+	MatrixSummary = "matrix-summary.schema.json" // Matrix summary (*_summary.json)
+	Comparison    = "comparison.schema.json"     // Several commands of one invocation (*_comparison.json)
+	Scan          = "scan.schema.json"           // Parameter scan (*_scan.json)
+	Diff          = "diff.schema.json"           // caliper compare --json of two results
+	MatrixDiff    = "matrix-diff.schema.json"    // caliper compare --json of two matrix summaries
+)
+
+//go:embed *.schema.json
+var files embed.FS
+
+// Source returns the JSON Schema with the given file name
+func Source(name string) ([]byte, error) {
+	return files.ReadFile(name)
+}
+
+// Validate checks a JSON document against the schema with the given file name
+// and returns its violations, e.g. "/runs/3/Status: must be one of ...".
+// The error is only set when the document or schema is not valid JSON.
+func Validate(name string, data []byte) ([]string, error) {
+	v := &validator{schemas: make(map[string]map[string]any)}
+	root, err := v.load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	v.validate(name, root, document, "")
+	return v.violations, nil
+}
+
+// validator collects the violations of a document
+type validator struct {
+	schemas    map[string]map[string]any // Loaded schema files by name
+	violations []string
+}
+
+// load parses an embedded schema file
+func (v *validator) load(name string) (map[string]any, error) {
+	if schema, ok := v.schemas[name]; ok {
+		return schema, nil
+	}
+	data, err := files.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown schema %q", name)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("schema %s: %w", name, err)
+	}
+	v.schemas[name] = schema
+	return schema, nil
+}
+
+// resolve looks up a $ref such as "#/$defs/run" or "result.schema.json#/$defs/run"
+// and returns the file it lives in along with the referenced schema
+func (v *validator) resolve(file, ref string) (string, map[string]any, error) {
+	target, pointer, _ := strings.Cut(ref, "#")
+	if target != "" {
+		file = target
+	}
+	schema, err := v.load(file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var node any = schema
+	for _, key := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if key == "" {
+			continue
+		}
+		object, ok := node.(map[string]any)
+		if !ok {
+			return "", nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		if node, ok = object[key]; !ok {
+			return "", nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+	resolved, ok := node.(map[string]any)
+	if !ok {
+		return "", nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	return file, resolved, nil
+}
+
+// fail records a violation at a JSON pointer
+func (v *validator) fail(path, format string, args ...any) {
+	if path == "" {
+		path = "/"
+	}
+	v.violations = append(v.violations, path+": "+fmt.Sprintf(format, args...))
+}
+
+// validate checks a value against a schema of the given file
+func (v *validator) validate(file string, schema map[string]any, value any, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		refFile, resolved, err := v.resolve(file, ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+			return
+		}
+		v.validate(refFile, resolved, value, path)
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, value) {
+		v.fail(path, "must be %s, not %s", describeType(types), typeOf(value))
+		return
+	}
+	if expected, ok := schema["const"]; ok && !equal(expected, value) {
+		v.fail(path, "must be %s", formatValue(expected))
+	}
+	if options, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, option := range options {
+			if equal(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			formatted := make([]string, len(options))
+			for i, option := range options {
+				formatted[i] = formatValue(option)
+			}
+			v.fail(path, "must be one of %s, not %s", strings.Join(formatted, ", "), formatValue(value))
+		}
+	}
+
+	switch value := value.(type) {
+	case json.Number:
+		n, _ := value.Float64()
+		if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+			v.fail(path, "must be at least %s, not %s", formatValue(minimum), value)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+			v.fail(path, "must be at most %s, not %s", formatValue(maximum), value)
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				v.validate(file, items, item, path+"/"+strconv.Itoa(i))
+			}
+		}
+	case map[string]any:
+		v.validateObject(file, schema, value, path)
+	}
+}
+
+// validateObject checks the required, properties and additionalProperties keywords
+func (v *validator) validateObject(file string, schema map[string]any, object map[string]any, path string) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, key := range required {
+			if _, ok := object[key.(string)]; !ok {
+				v.fail(path, "missing required field %q", key)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + key
+		if property, ok := properties[key].(map[string]any); ok {
+			v.validate(file, property, object[key], childPath)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(childPath, "unknown field")
+			}
+		case map[string]any:
+			v.validate(file, additional, object[key], childPath)
+		}
+	}
+}
+
+// matchesType reports whether a value has the type, or one of the types, of a "type" keyword
+func matchesType(types, value any) bool {
+	switch types := types.(type) {
+	case string:
+		return hasType(types, value)
+	case []any:
+		for _, t := range types {
+			if name, ok := t.(string); ok && hasType(name, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasType reports whether a value is of a JSON Schema type
+func hasType(name string, value any) bool {
+	switch name {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return typeOf(value) == name
+	}
+}
+
+// typeOf returns the JSON type of a decoded value
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// describeType formats a "type" keyword, e.g. "array or null"
+func describeType(types any) string {
+	if list, ok := types.([]any); ok {
+		names := make([]string, len(list))
+		for i, t := range list {
+			names[i] = fmt.Sprint(t)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(types)
+}
+
+// equal compares a schema value with a document value; numbers compare by value
+func equal(expected, value any) bool {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		e, isNumber := expected.(float64)
+		return err == nil && isNumber && f == e
+	}
+	return expected == value
+}
+
+// formatValue formats a value for a violation message
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		return value.String()
+	case string:
+		return strconv.Quote(value)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(value)
+	}
+}